@@ -0,0 +1,167 @@
+package s
+
+import (
+	"strings"
+)
+
+// ansiSequenceLen returns the length in bytes of the ANSI escape sequence at the
+// start of s, or 0 if s does not start with one. CSI sequences (ESC [ ... final),
+// OSC and other string sequences (ESC ] ... BEL or ST) and two-character escapes
+// are recognized; an unterminated sequence runs to the end of s.
+func ansiSequenceLen(s string) int {
+	if len(s) < 2 || s[0] != '\x1b' {
+		return 0
+	}
+
+	switch s[1] {
+	case '[':
+		// CSI: parameter bytes, intermediate bytes, then one final byte
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7E {
+				return i + 1
+			}
+			if s[i] < 0x20 || s[i] > 0x3F {
+				// Not a valid CSI byte, end the sequence before it
+				return i
+			}
+		}
+		return len(s)
+	case ']', 'P', 'X', '^', '_':
+		// OSC, DCS, SOS, PM and APC run until BEL or ST (ESC \)
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	}
+
+	// Other escapes: intermediate bytes followed by one final byte
+	for i := 1; i < len(s); i++ {
+		if s[i] >= 0x30 && s[i] <= 0x7E {
+			return i + 1
+		}
+		if s[i] < 0x20 || s[i] > 0x2F {
+			return 0
+		}
+	}
+	return len(s)
+}
+
+// eachANSIToken calls callback for every escape sequence and every grapheme
+// cluster of s, in order.
+func eachANSIToken(s string, callback func(token string, escape bool)) {
+	for len(s) > 0 {
+		if n := ansiSequenceLen(s); n > 0 {
+			callback(s[:n], true)
+			s = s[n:]
+			continue
+		}
+		n := nextGrapheme(s)
+		callback(s[:n], false)
+		s = s[n:]
+	}
+}
+
+// StripANSI removes all ANSI escape sequences (colors, cursor movement,
+// hyperlinks and the like) from s.
+func StripANSI(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+
+	var builder strings.Builder
+	builder.Grow(len(s))
+	eachANSIToken(s, func(token string, escape bool) {
+		if !escape {
+			builder.WriteString(token)
+		}
+	})
+	return builder.String()
+}
+
+// VisibleLen is like LenRune but does not count ANSI escape sequences.
+func VisibleLen(s string) int {
+	return LenRune(StripANSI(s))
+}
+
+// VisibleWidth is like DisplayWidth but treats ANSI escape sequences as zero-width.
+func VisibleWidth(s string) int {
+	return DisplayWidth(StripANSI(s))
+}
+
+// LeftPadANSI is like LeftPadWidth but ignores ANSI escape sequences in s when
+// measuring it, so colored text lines up in a terminal.
+func LeftPadANSI(s string, padStr string, width int) string {
+	sWidth := VisibleWidth(s)
+	if sWidth >= width || DisplayWidth(padStr) == 0 {
+		return s
+	}
+	return fillWidth(padStr, width-sWidth) + s
+}
+
+// RightPadANSI is like RightPadWidth but ignores ANSI escape sequences in s.
+func RightPadANSI(s string, padStr string, width int) string {
+	sWidth := VisibleWidth(s)
+	if sWidth >= width || DisplayWidth(padStr) == 0 {
+		return s
+	}
+	return s + fillWidth(padStr, width-sWidth)
+}
+
+// PadANSI is like PadWidth but ignores ANSI escape sequences in s.
+func PadANSI(s string, padStr string, width int) string {
+	sWidth := VisibleWidth(s)
+	if sWidth >= width || DisplayWidth(padStr) == 0 {
+		return s
+	}
+	left, right := splitPad(sWidth, DisplayWidth(padStr), width)
+	return fillWidth(padStr, left) + s + fillWidth(padStr, right)
+}
+
+// TruncateANSI is like TruncateWidth but treats ANSI escape sequences as zero-width
+// and never cuts one in half. Escape sequences after the cut are kept, so styles
+// opened before it are still reset.
+func TruncateANSI(s string, width int, ellipsis string) string {
+	if VisibleWidth(s) <= width {
+		return s
+	}
+
+	ellipsisWidth := DisplayWidth(ellipsis)
+	if ellipsisWidth > width {
+		ellipsis, ellipsisWidth = takeWidth(ellipsis, width)
+	}
+	limit := width - ellipsisWidth
+
+	var builder strings.Builder
+	builder.Grow(len(s) + len(ellipsis))
+	taken := 0
+	cut := false
+	eachANSIToken(s, func(token string, escape bool) {
+		if escape {
+			builder.WriteString(token)
+			return
+		}
+		if cut {
+			return
+		}
+		w := graphemeWidth(token)
+		if taken+w > limit {
+			builder.WriteString(ellipsis)
+			cut = true
+			return
+		}
+		taken += w
+		builder.WriteString(token)
+	})
+	return builder.String()
+}
+
+// WrapANSI is like Wrap but measures width in terminal cells and treats ANSI
+// escape sequences as zero-width.
+func WrapANSI(s string, width int) string {
+	return WrapWithOptions(s, width, WrapOptions{Measure: MeasureANSI})
+}
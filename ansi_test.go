@@ -0,0 +1,131 @@
+package s
+
+import "testing"
+
+const (
+	red   = "\x1b[31m"
+	reset = "\x1b[0m"
+)
+
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"plain", "plain"},
+		{red + "error" + reset, "error"},
+		{"\x1b[1;38;5;196mbold\x1b[m text", "bold text"},
+		{"\x1b]8;;https://example.com\x07link\x1b]8;;\x07", "link"},
+		{"\x1b]0;title\x1b\\text", "text"},
+		{"\x1b(Bascii", "ascii"},
+		{"\x1bcreset", "reset"},
+		{"\x1b[2J\x1b[Hhome", "home"},
+		{"unterminated \x1b[31", "unterminated "},
+		{"你好" + red + "世界" + reset, "你好世界"},
+	}
+
+	for _, test := range tests {
+		result := StripANSI(test.input)
+		if result != test.expected {
+			t.Errorf("StripANSI(%q) = %q; want %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestVisibleLen(t *testing.T) {
+	tests := []struct {
+		input       string
+		expectedLen int
+		expectedW   int
+	}{
+		{"", 0, 0},
+		{red + "error" + reset, 5, 5},
+		{Highlight("hello world", "o", red, reset), 11, 11},
+		{red + "你好" + reset, 2, 4},
+	}
+
+	for _, test := range tests {
+		if result := VisibleLen(test.input); result != test.expectedLen {
+			t.Errorf("VisibleLen(%q) = %d; want %d", test.input, result, test.expectedLen)
+		}
+		if result := VisibleWidth(test.input); result != test.expectedW {
+			t.Errorf("VisibleWidth(%q) = %d; want %d", test.input, result, test.expectedW)
+		}
+	}
+}
+
+func TestPadANSI(t *testing.T) {
+	highlighted := Highlight("id=42", `\d+`, red, reset)
+
+	tests := []struct {
+		name     string
+		fn       func(string, string, int) string
+		s        string
+		padStr   string
+		width    int
+		expected string
+	}{
+		{"LeftPadANSI", LeftPadANSI, highlighted, " ", 8, "   " + highlighted},
+		{"RightPadANSI", RightPadANSI, highlighted, " ", 8, highlighted + "   "},
+		{"RightPadANSI_CJK", RightPadANSI, red + "你好" + reset, ".", 6, red + "你好" + reset + ".."},
+		{"RightPadANSI_NoChange", RightPadANSI, highlighted, " ", 5, highlighted},
+		{"PadANSI", PadANSI, highlighted, "*", 8, "**" + highlighted + "*"},
+		{"PadANSI_EmptyPad", PadANSI, highlighted, "", 8, highlighted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.fn(tt.s, tt.padStr, tt.width)
+			if got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTruncateANSI(t *testing.T) {
+	tests := []struct {
+		s        string
+		width    int
+		ellipsis string
+		expected string
+	}{
+		{red + "hello" + reset, 5, "…", red + "hello" + reset},
+		{red + "hello world" + reset, 6, "…", red + "hello…" + reset},
+		{"ab" + red + "cdef" + reset, 3, "", "ab" + red + "c" + reset},
+		{red + "你好世界" + reset, 5, "…", red + "你好…" + reset},
+		{"\x1b]8;;https://example.com\x07click here\x1b]8;;\x07", 5, "", "\x1b]8;;https://example.com\x07click\x1b]8;;\x07"},
+	}
+
+	for _, test := range tests {
+		result := TruncateANSI(test.s, test.width, test.ellipsis)
+		if result != test.expected {
+			t.Errorf("TruncateANSI(%q, %d, %q) = %q; want %q",
+				test.s, test.width, test.ellipsis, result, test.expected)
+		}
+	}
+}
+
+func TestWrapANSI(t *testing.T) {
+	tests := []struct {
+		s        string
+		width    int
+		expected string
+	}{
+		{"", 10, ""},
+		{"the quick brown fox", 10, "the quick\nbrown fox"},
+		{"the " + red + "quick" + reset + " brown fox", 10, "the " + red + "quick" + reset + "\nbrown fox"},
+		{red + "aaaa bbbb" + reset, 4, red + "aaaa\nbbbb" + reset},
+		{"one\r\ntwo three", 5, "one\r\ntwo\r\nthree"},
+		{"averyveryverylongword x", 5, "averyveryverylongword\nx"},
+		{"你好 世界", 4, "你好\n世界"},
+	}
+
+	for _, test := range tests {
+		result := WrapANSI(test.s, test.width)
+		if result != test.expected {
+			t.Errorf("WrapANSI(%q, %d) = %q; want %q", test.s, test.width, result, test.expected)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package s
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DefaultRegexCacheSize is the number of compiled patterns kept by the
+// package-level regex cache unless changed with SetRegexCacheSize.
+const DefaultRegexCacheSize = 256
+
+// RegexCacheStats is a snapshot of the package-level regex cache.
+type RegexCacheStats struct {
+	Hits     uint64
+	Misses   uint64
+	Size     int
+	Capacity int
+}
+
+type regexCacheEntry struct {
+	pattern string
+	p       *Pattern
+}
+
+// regexCache is a concurrency-safe LRU cache of compiled patterns.
+type regexCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+	hits     uint64
+	misses   uint64
+}
+
+func newRegexCache(capacity int) *regexCache {
+	return &regexCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+var defaultRegexCache = newRegexCache(DefaultRegexCacheSize)
+
+// compile returns the compiled form of pattern, reusing a cached copy when
+// one exists. Patterns that fail to compile are not cached; the returned
+// error is a *PatternError.
+func (c *regexCache) compile(pattern string) (*Pattern, error) {
+	c.mu.Lock()
+	if elem, ok := c.entries[pattern]; ok {
+		c.order.MoveToFront(elem)
+		c.hits++
+		p := elem.Value.(*regexCacheEntry).p
+		c.mu.Unlock()
+		return p, nil
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	// Compile outside the lock so a slow pattern doesn't block other callers
+	p, err := Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.capacity <= 0 {
+		return p, nil
+	}
+	if elem, ok := c.entries[pattern]; ok {
+		// Another goroutine compiled the same pattern in the meantime
+		c.order.MoveToFront(elem)
+		return elem.Value.(*regexCacheEntry).p, nil
+	}
+	c.entries[pattern] = c.order.PushFront(&regexCacheEntry{pattern: pattern, p: p})
+	c.evict()
+	return p, nil
+}
+
+// evict drops least recently used entries until the cache fits its capacity.
+// The caller must hold c.mu.
+func (c *regexCache) evict() {
+	for c.order.Len() > c.capacity {
+		elem := c.order.Back()
+		c.order.Remove(elem)
+		delete(c.entries, elem.Value.(*regexCacheEntry).pattern)
+	}
+}
+
+func (c *regexCache) resize(capacity int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if capacity < 0 {
+		capacity = 0
+	}
+	c.capacity = capacity
+	c.evict()
+}
+
+func (c *regexCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+	c.hits = 0
+	c.misses = 0
+}
+
+func (c *regexCache) stats() RegexCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return RegexCacheStats{
+		Hits:     c.hits,
+		Misses:   c.misses,
+		Size:     c.order.Len(),
+		Capacity: c.capacity,
+	}
+}
+
+// compilePattern compiles pattern through the package-level cache. The
+// returned error is a *PatternError.
+func compilePattern(pattern string) (*Pattern, error) {
+	return defaultRegexCache.compile(pattern)
+}
+
+// SetRegexCacheSize sets how many compiled patterns the regex helpers keep.
+// Shrinking the cache evicts the least recently used patterns; a size of 0
+// disables caching.
+func SetRegexCacheSize(size int) {
+	defaultRegexCache.resize(size)
+}
+
+// ClearRegexCache drops every cached pattern and resets the hit/miss counters.
+func ClearRegexCache() {
+	defaultRegexCache.clear()
+}
+
+// GetRegexCacheStats returns the current hit/miss counters, size and capacity
+// of the regex cache.
+func GetRegexCacheStats() RegexCacheStats {
+	return defaultRegexCache.stats()
+}
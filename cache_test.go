@@ -0,0 +1,182 @@
+package s
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRegexCache(t *testing.T) {
+	c := newRegexCache(2)
+
+	if _, err := c.compile("a+"); err != nil {
+		t.Fatalf("compile(%q) returned error: %v", "a+", err)
+	}
+	if _, err := c.compile("a+"); err != nil {
+		t.Fatalf("compile(%q) returned error: %v", "a+", err)
+	}
+	if _, err := c.compile("["); err == nil {
+		t.Errorf("compile(%q) = nil error; want error", "[")
+	}
+
+	got := c.stats()
+	want := RegexCacheStats{Hits: 1, Misses: 2, Size: 1, Capacity: 2}
+	if got != want {
+		t.Errorf("stats() = %+v; want %+v", got, want)
+	}
+
+	// Touch "a+" so "b+" becomes the least recently used entry
+	c.compile("b+")
+	c.compile("a+")
+	c.compile("c+")
+	if _, ok := c.entries["b+"]; ok {
+		t.Errorf("least recently used pattern %q was not evicted", "b+")
+	}
+	if _, ok := c.entries["a+"]; !ok {
+		t.Errorf("recently used pattern %q was evicted", "a+")
+	}
+
+	c.resize(1)
+	if got := c.stats().Size; got != 1 {
+		t.Errorf("after resize(1) size = %d; want 1", got)
+	}
+
+	c.clear()
+	got = c.stats()
+	want = RegexCacheStats{Capacity: 1}
+	if got != want {
+		t.Errorf("after clear() stats() = %+v; want %+v", got, want)
+	}
+
+	c.resize(0)
+	c.compile("a+")
+	if got := c.stats().Size; got != 0 {
+		t.Errorf("with capacity 0 size = %d; want 0", got)
+	}
+}
+
+func TestRegexCacheConcurrent(t *testing.T) {
+	c := newRegexCache(8)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				pattern := fmt.Sprintf("x%d", (i+j)%12)
+				re, err := c.compile(pattern)
+				if err != nil || re.String() != pattern {
+					t.Errorf("compile(%q) = %v, %v", pattern, re, err)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	stats := c.stats()
+	if stats.Size > 8 {
+		t.Errorf("size = %d; want at most 8", stats.Size)
+	}
+	if stats.Hits+stats.Misses != 16*200 {
+		t.Errorf("hits+misses = %d; want %d", stats.Hits+stats.Misses, 16*200)
+	}
+}
+
+func TestRegexCacheHelpers(t *testing.T) {
+	defer SetRegexCacheSize(DefaultRegexCacheSize)
+	ClearRegexCache()
+
+	IsMatch("hello world", "wor")
+	IsMatch("hello world", "wor")
+	Grep("hello world", "o")
+
+	stats := GetRegexCacheStats()
+	if stats.Hits != 1 || stats.Misses != 2 {
+		t.Errorf("GetRegexCacheStats() = %+v; want 1 hit and 2 misses", stats)
+	}
+
+	SetRegexCacheSize(1)
+	if stats := GetRegexCacheStats(); stats.Size != 1 || stats.Capacity != 1 {
+		t.Errorf("after SetRegexCacheSize(1) stats = %+v", stats)
+	}
+}
+
+func BenchmarkIsMatchCached(b *testing.B) {
+	ClearRegexCache()
+	for i := 0; i < b.N; i++ {
+		IsMatch("2024-12-27 ERROR connection refused", `ERROR|FATAL`)
+	}
+}
+
+func BenchmarkIsMatchUncached(b *testing.B) {
+	defer SetRegexCacheSize(DefaultRegexCacheSize)
+	SetRegexCacheSize(0)
+	for i := 0; i < b.N; i++ {
+		IsMatch("2024-12-27 ERROR connection refused", `ERROR|FATAL`)
+	}
+}
+
+func BenchmarkGrepCached(b *testing.B) {
+	ClearRegexCache()
+	for i := 0; i < b.N; i++ {
+		Grep("id=12 id=34 id=56", `id=(\d+)`)
+	}
+}
+
+func BenchmarkGrepUncached(b *testing.B) {
+	defer SetRegexCacheSize(DefaultRegexCacheSize)
+	SetRegexCacheSize(0)
+	for i := 0; i < b.N; i++ {
+		Grep("id=12 id=34 id=56", `id=(\d+)`)
+	}
+}
+
+// highlightLog is a log of about 480 KB with two matches of the rules in
+// highlightRules on every line.
+var highlightLog = strings.Repeat("2024-12-27 12:00:01 ERROR id=4211 connection refused\n", 9000)
+
+var highlightRules = []HighlightRule{
+	{Pattern: `ERROR|WARN`, Left: "<red>", Right: "</red>"},
+	{Pattern: `\d+`, Left: "<yellow>", Right: "</yellow>"},
+}
+
+func BenchmarkHighlightLarge(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Highlight(highlightLog, `ERROR|\d+`, "<b>", "</b>")
+	}
+}
+
+func BenchmarkHighlightManyLarge(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		HighlightMany(highlightLog, highlightRules)
+	}
+}
+
+func BenchmarkHighlightManyNestLarge(b *testing.B) {
+	opts := HighlightOptions{Overlap: OverlapNest}
+	for i := 0; i < b.N; i++ {
+		HighlightManyWithOptions(highlightLog, highlightRules, opts)
+	}
+}
+
+func BenchmarkHighlightHTMLLarge(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		HighlightHTML(highlightLog, `ERROR|\d+`, "<mark>", "</mark>")
+	}
+}
+
+func BenchmarkHighlightWithOptionsLarge(b *testing.B) {
+	opts := HighlightOptions{IgnoreCase: true, WholeWord: true}
+	for i := 0; i < b.N; i++ {
+		HighlightWithOptions(highlightLog, `error|\d+`, "<b>", "</b>", opts)
+	}
+}
+
+func BenchmarkToWindowsLineEnding(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		ToWindowsLineEnding("line one\nline two\r\nline three\n")
+	}
+}
@@ -0,0 +1,244 @@
+package s
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// CaseConverter converts identifiers and phrases between cases like ToCamelCase
+// and the other case functions, but knows a list of acronyms. Known acronyms
+// keep their upper case in camel, pascal, title and sentence case ("user_id"
+// becomes "UserID" rather than "UserId") and runs of capitals made of them are
+// split apart ("HTTPAPIClient" becomes "http_api_client").
+//
+// The package-level case functions know no acronyms. A CaseConverter cannot be
+// changed once created, so it is safe for concurrent use and one converter's
+// acronyms never affect another's. The zero value knows no acronyms.
+type CaseConverter struct {
+	acronyms map[string]bool
+}
+
+// defaultCaseConverter is used by the package-level case functions.
+var defaultCaseConverter = &CaseConverter{}
+
+// NewCaseConverter returns a CaseConverter that knows the given acronyms, in any case.
+//
+// Example:
+//
+//	c := NewCaseConverter("ID", "HTTP")
+//	c.ToPascalCase("http_user_id") // Returns "HTTPUserID"
+func NewCaseConverter(acronyms ...string) *CaseConverter {
+	c := &CaseConverter{acronyms: make(map[string]bool, len(acronyms))}
+	for _, word := range acronyms {
+		if word != "" {
+			c.acronyms[strings.ToUpper(word)] = true
+		}
+	}
+	return c
+}
+
+// Acronyms returns the acronyms known to c, in upper case and sorted.
+func (c *CaseConverter) Acronyms() []string {
+	words := make([]string, 0, len(c.acronyms))
+	for word := range c.acronyms {
+		words = append(words, word)
+	}
+	slices.Sort(words)
+	return words
+}
+
+// SplitWords splits an identifier or phrase into words, as the case conversion
+// functions see them. Any character that is not a letter or digit separates
+// words, as do case changes: "fooBar" splits into "foo" and "Bar", and a run of
+// capitals followed by a lower case letter keeps the last capital for the next
+// word, so "HTTPServer" splits into "HTTP" and "Server". Digits stay with the
+// letters before them ("utf8Decoder" splits into "utf8" and "Decoder").
+func SplitWords(s string) []string {
+	return defaultCaseConverter.SplitWords(s)
+}
+
+// SplitWords is like the package-level SplitWords but also splits runs of
+// capitals made up of known acronyms into those acronyms.
+func (c *CaseConverter) SplitWords(s string) []string {
+	runes := []rune(s)
+	var words []string
+	start := -1
+
+	emit := func(end int) {
+		if start >= 0 && start < end {
+			words = append(words, c.splitAcronyms(string(runes[start:end]))...)
+		}
+		start = -1
+	}
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			emit(i)
+			continue
+		}
+		if start < 0 {
+			start = i
+			continue
+		}
+
+		prev := runes[i-1]
+		switch {
+		case unicode.IsUpper(r) && !unicode.IsUpper(prev):
+			// "fooBar", "utf8Decoder"
+			emit(i)
+			start = i
+		case unicode.IsUpper(r) && unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+			// "HTTPServer"
+			emit(i)
+			start = i
+		}
+	}
+	emit(len(runes))
+
+	return words
+}
+
+// splitAcronyms splits an all-capitals word made up entirely of known acronyms
+// into those acronyms, preferring the longest match. Other words are returned
+// unchanged.
+func (c *CaseConverter) splitAcronyms(word string) []string {
+	if len(c.acronyms) == 0 || strings.ToUpper(word) != word || c.acronyms[word] {
+		return []string{word}
+	}
+
+	var parts []string
+	rest := word
+	for rest != "" {
+		found := ""
+		for acronym := range c.acronyms {
+			if len(acronym) > len(found) && strings.HasPrefix(rest, acronym) {
+				found = acronym
+			}
+		}
+		if found == "" {
+			return []string{word}
+		}
+		parts = append(parts, found)
+		rest = rest[len(found):]
+	}
+	return parts
+}
+
+// isAcronym reports whether word is a known acronym.
+func (c *CaseConverter) isAcronym(word string) bool {
+	return c.acronyms[strings.ToUpper(word)]
+}
+
+// capitalize returns word with its first letter in title case and the rest in
+// lower case, or in upper case if it is a known acronym.
+func (c *CaseConverter) capitalize(word string) string {
+	if c.isAcronym(word) {
+		return strings.ToUpper(word)
+	}
+	runes := []rune(strings.ToLower(word))
+	runes[0] = unicode.ToTitle(runes[0])
+	return string(runes)
+}
+
+// joinWords converts each word of s with convert and joins them with sep.
+func (c *CaseConverter) joinWords(s string, sep string, convert func(word string, index int) string) string {
+	words := c.SplitWords(s)
+	for i, word := range words {
+		words[i] = convert(word, i)
+	}
+	return strings.Join(words, sep)
+}
+
+// ToCamelCase converts s to camel case, e.g. "http_server_port" becomes "httpServerPort".
+func ToCamelCase(s string) string {
+	return defaultCaseConverter.ToCamelCase(s)
+}
+
+// ToCamelCase is like the package-level ToCamelCase but keeps known acronyms in upper case.
+func (c *CaseConverter) ToCamelCase(s string) string {
+	return c.joinWords(s, "", func(word string, i int) string {
+		if i == 0 {
+			return strings.ToLower(word)
+		}
+		return c.capitalize(word)
+	})
+}
+
+// ToPascalCase converts s to pascal case, e.g. "http_server_port" becomes "HttpServerPort".
+func ToPascalCase(s string) string {
+	return defaultCaseConverter.ToPascalCase(s)
+}
+
+// ToPascalCase is like the package-level ToPascalCase but keeps known acronyms in upper case.
+func (c *CaseConverter) ToPascalCase(s string) string {
+	return c.joinWords(s, "", func(word string, i int) string {
+		return c.capitalize(word)
+	})
+}
+
+// ToSnakeCase converts s to snake case, e.g. "HTTPServerPort" becomes "http_server_port".
+func ToSnakeCase(s string) string {
+	return defaultCaseConverter.ToSnakeCase(s)
+}
+
+// ToSnakeCase is like the package-level ToSnakeCase but splits runs of known acronyms.
+func (c *CaseConverter) ToSnakeCase(s string) string {
+	return c.joinWords(s, "_", func(word string, i int) string {
+		return strings.ToLower(word)
+	})
+}
+
+// ToKebabCase converts s to kebab case, e.g. "HTTPServerPort" becomes "http-server-port".
+func ToKebabCase(s string) string {
+	return defaultCaseConverter.ToKebabCase(s)
+}
+
+// ToKebabCase is like the package-level ToKebabCase but splits runs of known acronyms.
+func (c *CaseConverter) ToKebabCase(s string) string {
+	return c.joinWords(s, "-", func(word string, i int) string {
+		return strings.ToLower(word)
+	})
+}
+
+// ToConstantCase converts s to constant case, e.g. "httpServerPort" becomes "HTTP_SERVER_PORT".
+func ToConstantCase(s string) string {
+	return defaultCaseConverter.ToConstantCase(s)
+}
+
+// ToConstantCase is like the package-level ToConstantCase but splits runs of known acronyms.
+func (c *CaseConverter) ToConstantCase(s string) string {
+	return c.joinWords(s, "_", func(word string, i int) string {
+		return strings.ToUpper(word)
+	})
+}
+
+// ToTitleCase converts s to title case, e.g. "http_server_port" becomes "Http Server Port".
+func ToTitleCase(s string) string {
+	return defaultCaseConverter.ToTitleCase(s)
+}
+
+// ToTitleCase is like the package-level ToTitleCase but keeps known acronyms in upper case.
+func (c *CaseConverter) ToTitleCase(s string) string {
+	return c.joinWords(s, " ", func(word string, i int) string {
+		return c.capitalize(word)
+	})
+}
+
+// ToSentenceCase converts s to sentence case, e.g. "httpServerPort" becomes "Http server port".
+func ToSentenceCase(s string) string {
+	return defaultCaseConverter.ToSentenceCase(s)
+}
+
+// ToSentenceCase is like the package-level ToSentenceCase but keeps known acronyms in upper case.
+func (c *CaseConverter) ToSentenceCase(s string) string {
+	return c.joinWords(s, " ", func(word string, i int) string {
+		if i == 0 {
+			return c.capitalize(word)
+		}
+		if c.isAcronym(word) {
+			return strings.ToUpper(word)
+		}
+		return strings.ToLower(word)
+	})
+}
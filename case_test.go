@@ -0,0 +1,134 @@
+package s
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"", nil},
+		{"hello", []string{"hello"}},
+		{"helloWorld", []string{"hello", "World"}},
+		{"HelloWorld", []string{"Hello", "World"}},
+		{"hello_world", []string{"hello", "world"}},
+		{"hello-world", []string{"hello", "world"}},
+		{"hello world", []string{"hello", "world"}},
+		{"  __hello--world__  ", []string{"hello", "world"}},
+		{"HTTPServer", []string{"HTTP", "Server"}},
+		{"getHTTPResponse", []string{"get", "HTTP", "Response"}},
+		{"HTTP", []string{"HTTP"}},
+		{"utf8Decoder", []string{"utf8", "Decoder"}},
+		{"Version2Beta", []string{"Version2", "Beta"}},
+		{"ABC123", []string{"ABC123"}},
+		{"2ndPlace", []string{"2nd", "Place"}},
+		{"file.name.txt", []string{"file", "name", "txt"}},
+		{"ÜberCoolÉtude", []string{"Über", "Cool", "Étude"}},
+		{"привет_мир", []string{"привет", "мир"}},
+		{"日本語Text", []string{"日本語", "Text"}},
+	}
+
+	for _, test := range tests {
+		result := SplitWords(test.input)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("SplitWords(%q) = %q; want %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestCaseConversion(t *testing.T) {
+	tests := []struct {
+		input    string
+		camel    string
+		pascal   string
+		snake    string
+		kebab    string
+		constant string
+		title    string
+		sentence string
+	}{
+		{"", "", "", "", "", "", "", ""},
+		{"hello world", "helloWorld", "HelloWorld", "hello_world", "hello-world", "HELLO_WORLD", "Hello World", "Hello world"},
+		{"HTTPServer", "httpServer", "HttpServer", "http_server", "http-server", "HTTP_SERVER", "Http Server", "Http server"},
+		{"http_server_port", "httpServerPort", "HttpServerPort", "http_server_port", "http-server-port", "HTTP_SERVER_PORT", "Http Server Port", "Http server port"},
+		{"XMLHttpRequest", "xmlHttpRequest", "XmlHttpRequest", "xml_http_request", "xml-http-request", "XML_HTTP_REQUEST", "Xml Http Request", "Xml http request"},
+		{"user-id", "userId", "UserId", "user_id", "user-id", "USER_ID", "User Id", "User id"},
+		{"MAX_RETRY_COUNT", "maxRetryCount", "MaxRetryCount", "max_retry_count", "max-retry-count", "MAX_RETRY_COUNT", "Max Retry Count", "Max retry count"},
+		{"utf8Decoder", "utf8Decoder", "Utf8Decoder", "utf8_decoder", "utf8-decoder", "UTF8_DECODER", "Utf8 Decoder", "Utf8 decoder"},
+		{"straße_größe", "straßeGröße", "StraßeGröße", "straße_größe", "straße-größe", "STRAßE_GRÖßE", "Straße Größe", "Straße größe"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := ToCamelCase(tt.input); got != tt.camel {
+				t.Errorf("ToCamelCase(%q) = %q; want %q", tt.input, got, tt.camel)
+			}
+			if got := ToPascalCase(tt.input); got != tt.pascal {
+				t.Errorf("ToPascalCase(%q) = %q; want %q", tt.input, got, tt.pascal)
+			}
+			if got := ToSnakeCase(tt.input); got != tt.snake {
+				t.Errorf("ToSnakeCase(%q) = %q; want %q", tt.input, got, tt.snake)
+			}
+			if got := ToKebabCase(tt.input); got != tt.kebab {
+				t.Errorf("ToKebabCase(%q) = %q; want %q", tt.input, got, tt.kebab)
+			}
+			if got := ToConstantCase(tt.input); got != tt.constant {
+				t.Errorf("ToConstantCase(%q) = %q; want %q", tt.input, got, tt.constant)
+			}
+			if got := ToTitleCase(tt.input); got != tt.title {
+				t.Errorf("ToTitleCase(%q) = %q; want %q", tt.input, got, tt.title)
+			}
+			if got := ToSentenceCase(tt.input); got != tt.sentence {
+				t.Errorf("ToSentenceCase(%q) = %q; want %q", tt.input, got, tt.sentence)
+			}
+		})
+	}
+}
+
+func TestAcronyms(t *testing.T) {
+	c := NewCaseConverter("id", "HTTP", "api", "URL", "")
+
+	if got, want := c.Acronyms(), []string{"API", "HTTP", "ID", "URL"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Acronyms() = %q; want %q", got, want)
+	}
+
+	tests := []struct {
+		name     string
+		fn       func(string) string
+		input    string
+		expected string
+	}{
+		{"ToPascalCase", c.ToPascalCase, "user_id", "UserID"},
+		{"ToCamelCase", c.ToCamelCase, "user_id", "userID"},
+		{"ToCamelCase_LeadingAcronym", c.ToCamelCase, "http_server", "httpServer"},
+		{"ToPascalCase_Acronym", c.ToPascalCase, "http_server", "HTTPServer"},
+		{"ToSnakeCase_Run", c.ToSnakeCase, "HTTPAPIClient", "http_api_client"},
+		{"ToSnakeCase_UnknownRun", c.ToSnakeCase, "XYZClient", "xyz_client"},
+		{"ToTitleCase", c.ToTitleCase, "base_url", "Base URL"},
+		{"ToSentenceCase", c.ToSentenceCase, "api_base_url", "API base URL"},
+		{"ToConstantCase", c.ToConstantCase, "APIURL", "API_URL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fn(tt.input); got != tt.expected {
+				t.Errorf("%s(%q) = %q; want %q", tt.name, tt.input, got, tt.expected)
+			}
+		})
+	}
+
+	// Converters do not affect the package-level functions or each other
+	if got := ToPascalCase("user_id"); got != "UserId" {
+		t.Errorf("ToPascalCase(%q) = %q; want %q", "user_id", got, "UserId")
+	}
+	if got := NewCaseConverter("API").ToPascalCase("user_id"); got != "UserId" {
+		t.Errorf("NewCaseConverter(\"API\").ToPascalCase(%q) = %q; want %q", "user_id", got, "UserId")
+	}
+	var zero CaseConverter
+	if got := zero.ToSnakeCase("HTTPAPIClient"); got != "httpapi_client" {
+		t.Errorf("zero CaseConverter ToSnakeCase(%q) = %q; want %q", "HTTPAPIClient", got, "httpapi_client")
+	}
+}
@@ -0,0 +1,50 @@
+package s
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrUnknownGroup is returned when a group index or name does not exist in a pattern.
+var ErrUnknownGroup = errors.New("unknown regex group")
+
+// ErrInvalidGlob is returned when a glob pattern is malformed.
+var ErrInvalidGlob = errors.New("invalid glob")
+
+// PatternError records a pattern that failed to compile.
+type PatternError struct {
+	Pattern string
+	Err     error
+}
+
+func (e *PatternError) Error() string {
+	return "invalid pattern " + strconv.Quote(e.Pattern) + ": " + e.Err.Error()
+}
+
+func (e *PatternError) Unwrap() error {
+	return e.Err
+}
+
+// ErrNoMatch is returned by MatchInto when the pattern does not match.
+var ErrNoMatch = errors.New("no match")
+
+// ErrInvalidTarget is returned by MatchInto and MatchAllInto when the destination
+// is not a pointer to a struct or to a slice of structs.
+var ErrInvalidTarget = errors.New("invalid match target")
+
+// FieldError records a group value that could not be stored in a struct field.
+type FieldError struct {
+	Field string
+	Group string
+	Value string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return "cannot set field " + e.Field + " from group " + strconv.Quote(e.Group) +
+		" value " + strconv.Quote(e.Value) + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
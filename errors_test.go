@@ -0,0 +1,99 @@
+package s
+
+import (
+	"errors"
+	"reflect"
+	"regexp/syntax"
+	"testing"
+)
+
+func TestPatternErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"IsMatchE", func() error { _, err := IsMatchE("hello", "("); return err }},
+		{"GrepE", func() error { _, err := GrepE("hello", "("); return err }},
+		{"GrepGroupE", func() error { _, err := GrepGroupE("hello", "(", "1"); return err }},
+		{"GetMatchedRegexGroupE", func() error { _, err := GetMatchedRegexGroupE("hello", "(", "1"); return err }},
+		{"HighlightE", func() error { _, err := HighlightE("hello", "(", "[", "]"); return err }},
+		{"GrepGroupsE", func() error { _, err := GrepGroupsE("hello", "("); return err }},
+		{"MatchGroupsE", func() error { _, err := MatchGroupsE("hello", "("); return err }},
+		{"GrepLinesAsStringE", func() error { _, err := GrepLinesAsStringE("hello", "(", GrepOptions{}); return err }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+
+			var patternErr *PatternError
+			if !errors.As(err, &patternErr) {
+				t.Fatalf("%s error = %v; want *PatternError", tt.name, err)
+			}
+			if patternErr.Pattern != "(" {
+				t.Errorf("%s PatternError.Pattern = %q; want %q", tt.name, patternErr.Pattern, "(")
+			}
+
+			var syntaxErr *syntax.Error
+			if !errors.As(err, &syntaxErr) {
+				t.Errorf("%s error %v does not wrap *syntax.Error", tt.name, err)
+			}
+		})
+	}
+}
+
+func TestUnknownGroupErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		group   string
+		wantErr bool
+		want    []string
+	}{
+		{"numeric group", `(\d+)`, "1", false, []string{"123", "456"}},
+		{"whole match", `(\d+)`, "0", false, []string{"123", "456"}},
+		{"named group", `(?P<num>\d+)`, "num", false, []string{"123", "456"}},
+		{"index out of range", `(\d+)`, "2", true, []string{}},
+		{"negative index", `(\d+)`, "-1", true, []string{}},
+		{"unknown name", `(?P<num>\d+)`, "word", true, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GrepGroupE("abc123def456", tt.pattern, tt.group)
+			if gotErr := errors.Is(err, ErrUnknownGroup); gotErr != tt.wantErr {
+				t.Errorf("GrepGroupE error = %v; want ErrUnknownGroup: %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GrepGroupE = %q; want %q", got, tt.want)
+			}
+
+			first, err := GetMatchedRegexGroupE("abc123def456", tt.pattern, tt.group)
+			if gotErr := errors.Is(err, ErrUnknownGroup); gotErr != tt.wantErr {
+				t.Errorf("GetMatchedRegexGroupE error = %v; want ErrUnknownGroup: %v", err, tt.wantErr)
+			}
+			wantFirst := ""
+			if len(tt.want) > 0 {
+				wantFirst = tt.want[0]
+			}
+			if first != wantFirst {
+				t.Errorf("GetMatchedRegexGroupE = %q; want %q", first, wantFirst)
+			}
+		})
+	}
+}
+
+func TestErrorVariantsWithoutErrors(t *testing.T) {
+	if ok, err := IsMatchE("hello", "ell"); !ok || err != nil {
+		t.Errorf("IsMatchE(%q, %q) = %v, %v; want true, nil", "hello", "ell", ok, err)
+	}
+	if got, err := GrepE("hello", "z"); len(got) != 0 || got == nil || err != nil {
+		t.Errorf("GrepE(%q, %q) = %#v, %v; want empty slice, nil", "hello", "z", got, err)
+	}
+	if got, err := HighlightE("hello", "l+", "[", "]"); got != "he[ll]o" || err != nil {
+		t.Errorf("HighlightE = %q, %v; want %q, nil", got, err, "he[ll]o")
+	}
+	if got, err := GetMatchedRegexGroupE("hello", `(z)`, "1"); got != "" || err != nil {
+		t.Errorf("GetMatchedRegexGroupE without a match = %q, %v; want \"\", nil", got, err)
+	}
+}
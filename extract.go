@@ -0,0 +1,269 @@
+package s
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var (
+	durationType = reflect.TypeFor[time.Duration]()
+	timeType     = reflect.TypeFor[time.Time]()
+)
+
+// fieldBinding ties a struct field to the group whose value it receives.
+type fieldBinding struct {
+	index  []int
+	name   string
+	group  string
+	groupN int
+	layout string
+}
+
+// bindFields resolves the s struct tags of t to groups of the pattern. Fields
+// tagged with a group name or index receive that group; untagged fields and
+// fields tagged "-" are left alone. Tagged fields may be promoted from embedded
+// structs or exported embedded struct pointers. A time.Time field is parsed with the layout
+// given by its layout tag, or time.RFC3339 if there is none.
+func (p *Pattern) bindFields(t reflect.Type) ([]fieldBinding, error) {
+	var bindings []fieldBinding
+	for _, field := range reflect.VisibleFields(t) {
+		group, ok := field.Tag.Lookup("s")
+		if !ok || group == "-" || !field.IsExported() {
+			continue
+		}
+
+		groupIdx, err := p.groupIndex(group)
+		if err != nil {
+			return nil, err
+		}
+
+		// Nil embedded pointers are allocated when the field is set, which
+		// reflect does not allow for unexported ones
+		embedded := t
+		for _, x := range field.Index[:len(field.Index)-1] {
+			outer := embedded.Field(x)
+			embedded = outer.Type
+			if embedded.Kind() == reflect.Pointer {
+				if !outer.IsExported() {
+					return nil, fmt.Errorf("%w: field %s is promoted through the unexported embedded pointer %s",
+						ErrInvalidTarget, field.Name, outer.Type)
+				}
+				embedded = embedded.Elem()
+			}
+		}
+
+		layout := field.Tag.Get("layout")
+		if layout == "" {
+			layout = time.RFC3339
+		}
+
+		bindings = append(bindings, fieldBinding{
+			index:  field.Index,
+			name:   field.Name,
+			group:  group,
+			groupN: groupIdx,
+			layout: layout,
+		})
+	}
+	return bindings, nil
+}
+
+// fill stores the groups of a match, given as submatch locations in s, in the
+// bound fields of the struct v. Groups that did not take part in the match
+// leave their fields untouched; the others allocate the nil embedded struct
+// pointers their fields are promoted through.
+func fill(v reflect.Value, bindings []fieldBinding, s string, loc []int) error {
+	for _, b := range bindings {
+		start, end := loc[2*b.groupN], loc[2*b.groupN+1]
+		if start < 0 {
+			continue
+		}
+		value := s[start:end]
+		if err := setField(fieldByIndex(v, b.index), value, b.layout); err != nil {
+			return &FieldError{Field: b.name, Group: b.group, Value: value, Err: err}
+		}
+	}
+	return nil
+}
+
+// fieldByIndex returns the nested field of the struct v given by index, like
+// reflect.Value.FieldByIndex, but allocates the nil embedded struct pointers it
+// walks through.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// setField converts value to the type of field and stores it there. Pointer
+// fields are set to a newly allocated value.
+func setField(field reflect.Value, value string, layout string) error {
+	if field.Kind() == reflect.Pointer {
+		elem := reflect.New(field.Type().Elem())
+		if err := setField(elem.Elem(), value, layout); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+
+	switch field.Type() {
+	case durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	case timeType:
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
+
+// MatchInto matches the pattern against s and stores the groups of the first
+// match in the fields of the struct dst points to. Fields are bound to groups by
+// their s tag, which holds a group name or index. Strings, booleans, integers,
+// floats, time.Duration and time.Time fields are supported, as are pointers to
+// them; a time.Time field is parsed with the layout in its layout tag, or
+// time.RFC3339 by default. Fields whose group did not take part in the match are
+// left untouched.
+//
+// MatchInto returns ErrNoMatch if the pattern does not match, ErrInvalidTarget if
+// dst is not a non-nil pointer to a struct, an error wrapping ErrUnknownGroup if
+// a tag names a group that does not exist, and a *FieldError if a value cannot be
+// converted to its field's type.
+//
+// Example:
+//
+//	var entry struct {
+//		User    string        `s:"user"`
+//		Status  int           `s:"status"`
+//		Elapsed time.Duration `s:"elapsed"`
+//	}
+//	p := MustCompile(`user=(?P<user>\w+) status=(?P<status>\d+) took=(?P<elapsed>\S+)`)
+//	err := p.MatchInto("user=alice status=200 took=1.5ms", &entry)
+func (p *Pattern) MatchInto(s string, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: want a pointer to a struct, got %T", ErrInvalidTarget, dst)
+	}
+
+	bindings, err := p.bindFields(v.Elem().Type())
+	if err != nil {
+		return err
+	}
+
+	loc := p.re.FindStringSubmatchIndex(s)
+	if loc == nil {
+		return ErrNoMatch
+	}
+	return fill(v.Elem(), bindings, s, loc)
+}
+
+// MatchAllInto is like MatchInto but stores every match of the pattern in s,
+// appending one element per match to the slice dst points to. The slice elements
+// may be structs or pointers to structs. If there is no match, the slice is left
+// as is and nil is returned. If a value cannot be converted, the elements for
+// the matches before it have been appended.
+func (p *Pattern) MatchAllInto(s string, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("%w: want a pointer to a slice of structs, got %T", ErrInvalidTarget, dst)
+	}
+	slice := v.Elem()
+
+	elemType := slice.Type().Elem()
+	isPointer := elemType.Kind() == reflect.Pointer
+	structType := elemType
+	if isPointer {
+		structType = elemType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: want a pointer to a slice of structs, got %T", ErrInvalidTarget, dst)
+	}
+
+	bindings, err := p.bindFields(structType)
+	if err != nil {
+		return err
+	}
+
+	for i, loc := range p.re.FindAllStringSubmatchIndex(s, -1) {
+		elem := reflect.New(structType)
+		if err := fill(elem.Elem(), bindings, s, loc); err != nil {
+			return fmt.Errorf("match %d: %w", i, err)
+		}
+		if !isPointer {
+			elem = elem.Elem()
+		}
+		slice.Set(reflect.Append(slice, elem))
+	}
+	return nil
+}
+
+// MatchInto matches pattern against s and stores the groups of the first match
+// in the tagged fields of the struct dst points to. See Pattern.MatchInto. If the
+// pattern does not compile, a *PatternError is returned.
+func MatchInto(s string, pattern string, dst any) error {
+	p, err := compilePattern(pattern)
+	if err != nil {
+		return err
+	}
+	return p.MatchInto(s, dst)
+}
+
+// MatchAllInto matches pattern against s and appends one struct per match to the
+// slice dst points to. See Pattern.MatchAllInto. If the pattern does not compile,
+// a *PatternError is returned.
+func MatchAllInto(s string, pattern string, dst any) error {
+	p, err := compilePattern(pattern)
+	if err != nil {
+		return err
+	}
+	return p.MatchAllInto(s, dst)
+}
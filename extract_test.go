@@ -0,0 +1,264 @@
+package s
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type logEntry struct {
+	When     time.Time     `s:"when" layout:"2006-01-02 15:04:05"`
+	User     string        `s:"user"`
+	Status   int           `s:"status"`
+	Bytes    uint64        `s:"bytes"`
+	Ratio    float64       `s:"ratio"`
+	Cached   bool          `s:"cached"`
+	Elapsed  time.Duration `s:"elapsed"`
+	Note     *string       `s:"note"`
+	Raw      string        `s:"0"`
+	Ignored  string        `s:"-"`
+	Untagged string
+}
+
+const logPattern = `(?P<when>\S+ \S+) user=(?P<user>\w+) status=(?P<status>-?\d+) bytes=(?P<bytes>\d+) ` +
+	`ratio=(?P<ratio>[\d.]+) cached=(?P<cached>\w+) took=(?P<elapsed>\S+)(?: note=(?P<note>\w*))?`
+
+func TestMatchInto(t *testing.T) {
+	line := "2024-03-01 12:30:45 user=alice status=200 bytes=1024 ratio=0.75 cached=true took=1.5ms note=slow"
+
+	var entry logEntry
+	if err := MatchInto(line, logPattern, &entry); err != nil {
+		t.Fatalf("MatchInto error: %v", err)
+	}
+
+	note := "slow"
+	want := logEntry{
+		When:    time.Date(2024, 3, 1, 12, 30, 45, 0, time.UTC),
+		User:    "alice",
+		Status:  200,
+		Bytes:   1024,
+		Ratio:   0.75,
+		Cached:  true,
+		Elapsed: 1500 * time.Microsecond,
+		Note:    &note,
+		Raw:     line,
+	}
+	if !reflect.DeepEqual(entry, want) {
+		t.Errorf("MatchInto =\n%+v\nwant\n%+v", entry, want)
+	}
+}
+
+func TestMatchIntoOptionalGroups(t *testing.T) {
+	line := "2024-03-01 12:30:45 user=bob status=-1 bytes=0 ratio=1 cached=0 took=2s"
+
+	entry := logEntry{Ignored: "kept", Untagged: "kept"}
+	if err := MatchInto(line, logPattern, &entry); err != nil {
+		t.Fatalf("MatchInto error: %v", err)
+	}
+	if entry.Note != nil {
+		t.Errorf("Note = %q; want nil for a group that did not take part", *entry.Note)
+	}
+	if entry.Ignored != "kept" || entry.Untagged != "kept" {
+		t.Errorf("untagged fields were modified: %+v", entry)
+	}
+	if entry.Status != -1 || entry.Cached || entry.Elapsed != 2*time.Second {
+		t.Errorf("MatchInto = %+v", entry)
+	}
+
+	// A group that matched the empty string is set
+	entry = logEntry{}
+	if err := MatchInto(line+" note=", logPattern, &entry); err != nil {
+		t.Fatalf("MatchInto error: %v", err)
+	}
+	if entry.Note == nil || *entry.Note != "" {
+		t.Errorf("Note = %v; want pointer to empty string", entry.Note)
+	}
+}
+
+func TestMatchIntoDefaultTimeLayout(t *testing.T) {
+	var event struct {
+		At   time.Time `s:"1"`
+		Name string    `s:"2"`
+	}
+	if err := MatchInto("at 2024-03-01T10:00:00Z deploy", `at (\S+) (\w+)`, &event); err != nil {
+		t.Fatalf("MatchInto error: %v", err)
+	}
+	if !event.At.Equal(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)) || event.Name != "deploy" {
+		t.Errorf("MatchInto = %+v", event)
+	}
+}
+
+func TestMatchIntoErrors(t *testing.T) {
+	type target struct {
+		N int `s:"n"`
+	}
+
+	var dst target
+	if err := MatchInto("n=abc", `n=(?P<n>\w+)`, &dst); err == nil {
+		t.Error("MatchInto with unconvertible value returned no error")
+	} else {
+		var fieldErr *FieldError
+		if !errors.As(err, &fieldErr) {
+			t.Fatalf("error = %v; want *FieldError", err)
+		}
+		if fieldErr.Field != "N" || fieldErr.Group != "n" || fieldErr.Value != "abc" {
+			t.Errorf("FieldError = %+v", fieldErr)
+		}
+		var numErr *strconv.NumError
+		if !errors.As(err, &numErr) {
+			t.Errorf("error %v does not wrap *strconv.NumError", err)
+		}
+		if !strings.Contains(err.Error(), `field N from group "n" value "abc"`) {
+			t.Errorf("error message %q does not name the field, group and value", err.Error())
+		}
+	}
+
+	if err := MatchInto("n=300", `n=(?P<n>\d+)`, &struct {
+		N int8 `s:"n"`
+	}{}); !errors.Is(err, strconv.ErrRange) {
+		t.Errorf("overflow error = %v; want strconv.ErrRange", err)
+	}
+
+	if err := MatchInto("x", `n=(?P<n>\d+)`, &dst); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("no match error = %v; want ErrNoMatch", err)
+	}
+	if err := MatchInto("n=1", `n=(?P<m>\d+)`, &dst); !errors.Is(err, ErrUnknownGroup) {
+		t.Errorf("unknown group error = %v; want ErrUnknownGroup", err)
+	}
+	if err := MatchInto("n=1", `n=(?P<n>\d+)`, dst); !errors.Is(err, ErrInvalidTarget) {
+		t.Errorf("non-pointer error = %v; want ErrInvalidTarget", err)
+	}
+	if err := MatchInto("n=1", `n=(?P<n>\d+)`, (*target)(nil)); !errors.Is(err, ErrInvalidTarget) {
+		t.Errorf("nil pointer error = %v; want ErrInvalidTarget", err)
+	}
+	if err := MatchInto("n=1", `n=(?P<n>\d+)`, &struct {
+		N []int `s:"n"`
+	}{}); err == nil || !strings.Contains(err.Error(), "unsupported field type []int") {
+		t.Errorf("unsupported type error = %v", err)
+	}
+
+	var patternErr *PatternError
+	if err := MatchInto("n=1", `(`, &dst); !errors.As(err, &patternErr) {
+		t.Errorf("invalid pattern error = %v; want *PatternError", err)
+	}
+}
+
+func TestMatchAllInto(t *testing.T) {
+	type pair struct {
+		Key   string `s:"key"`
+		Value int    `s:"value"`
+	}
+	input := "a=1 b=2 c=3"
+	pattern := `(?P<key>\w)=(?P<value>\d)`
+
+	var pairs []pair
+	if err := MatchAllInto(input, pattern, &pairs); err != nil {
+		t.Fatalf("MatchAllInto error: %v", err)
+	}
+	want := []pair{{"a", 1}, {"b", 2}, {"c", 3}}
+	if !reflect.DeepEqual(pairs, want) {
+		t.Errorf("MatchAllInto = %+v; want %+v", pairs, want)
+	}
+
+	var pointers []*pair
+	if err := MatchAllInto(input, pattern, &pointers); err != nil {
+		t.Fatalf("MatchAllInto error: %v", err)
+	}
+	if len(pointers) != 3 || *pointers[2] != want[2] {
+		t.Errorf("MatchAllInto into pointers = %+v", pointers)
+	}
+
+	var none []pair
+	if err := MatchAllInto("nothing", pattern, &none); err != nil || len(none) != 0 {
+		t.Errorf("MatchAllInto with no match = %+v, %v; want empty, nil", none, err)
+	}
+
+	var partial []pair
+	err := MatchAllInto("a=1 b=x c=3", `(?P<key>\w)=(?P<value>\w)`, &partial)
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Value != "x" {
+		t.Errorf("MatchAllInto conversion error = %v; want *FieldError for %q", err, "x")
+	}
+	if len(partial) != 1 {
+		t.Errorf("MatchAllInto kept %d elements before the error; want 1", len(partial))
+	}
+
+	if err := MatchAllInto(input, pattern, &pair{}); !errors.Is(err, ErrInvalidTarget) {
+		t.Errorf("struct target error = %v; want ErrInvalidTarget", err)
+	}
+	if err := MatchAllInto(input, pattern, &[]string{}); !errors.Is(err, ErrInvalidTarget) {
+		t.Errorf("slice of strings error = %v; want ErrInvalidTarget", err)
+	}
+}
+
+// EmbeddedUser and EmbeddedStatus are exported so that nil pointers to them can
+// be allocated when they are embedded.
+type EmbeddedUser struct {
+	User string `s:"user"`
+}
+
+type EmbeddedStatus struct {
+	Status int `s:"status"`
+}
+
+type hiddenUser struct {
+	User string `s:"user"`
+}
+
+func TestMatchIntoEmbedded(t *testing.T) {
+	const line = "user=alice status=200"
+	const pattern = `user=(?P<user>\w+)(?: status=(?P<status>\d+))?`
+
+	var byValue struct {
+		hiddenUser
+		EmbeddedStatus
+	}
+	if err := MatchInto(line, pattern, &byValue); err != nil {
+		t.Fatalf("MatchInto into embedded values error: %v", err)
+	}
+	if byValue.User != "alice" || byValue.Status != 200 {
+		t.Errorf("MatchInto into embedded values = %+v", byValue)
+	}
+
+	var byPointer struct {
+		*EmbeddedUser
+		*EmbeddedStatus
+	}
+	if err := MatchInto(line, pattern, &byPointer); err != nil {
+		t.Fatalf("MatchInto into embedded pointers error: %v", err)
+	}
+	if byPointer.EmbeddedUser == nil || byPointer.User != "alice" ||
+		byPointer.EmbeddedStatus == nil || byPointer.Status != 200 {
+		t.Errorf("MatchInto into embedded pointers = %+v", byPointer)
+	}
+
+	// A group that does not take part leaves its embedded pointer nil
+	byPointer.EmbeddedUser, byPointer.EmbeddedStatus = nil, nil
+	if err := MatchInto("user=bob", pattern, &byPointer); err != nil {
+		t.Fatalf("MatchInto into embedded pointers error: %v", err)
+	}
+	if byPointer.EmbeddedUser == nil || byPointer.User != "bob" || byPointer.EmbeddedStatus != nil {
+		t.Errorf("MatchInto with optional group = %+v", byPointer)
+	}
+
+	var pairs []struct {
+		*EmbeddedUser
+		EmbeddedStatus
+	}
+	if err := MatchAllInto("user=a status=1 user=b status=2", pattern, &pairs); err != nil {
+		t.Fatalf("MatchAllInto into embedded pointers error: %v", err)
+	}
+	if len(pairs) != 2 || pairs[0].User != "a" || pairs[1].User != "b" || pairs[1].Status != 2 {
+		t.Errorf("MatchAllInto into embedded pointers = %+v", pairs)
+	}
+
+	var unexported struct {
+		*hiddenUser
+	}
+	if err := MatchInto(line, pattern, &unexported); !errors.Is(err, ErrInvalidTarget) {
+		t.Errorf("MatchInto into unexported embedded pointer error = %v; want ErrInvalidTarget", err)
+	}
+}
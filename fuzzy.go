@@ -0,0 +1,232 @@
+package s
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Scores used by FuzzyMatch. Every matched rune earns fuzzyScoreMatch plus a
+// bonus depending on where it lies in the candidate. A rune following another
+// matched rune earns at least fuzzyBonusConsecutive and at least the bonus of the
+// rune starting the run, so that whole words keep the bonus of their start. Gaps
+// between matched runes cost fuzzyGapStart for the first skipped rune and
+// fuzzyGapExtension for each further one.
+const (
+	fuzzyScoreMatch       = 16
+	fuzzyGapStart         = 3
+	fuzzyGapExtension     = 1
+	fuzzyBonusBoundary    = 8
+	fuzzyBonusCamel       = 7
+	fuzzyBonusConsecutive = 4
+	// The bonus of the rune matching the first query rune is multiplied by this
+	fuzzyFirstRuneMultiplier = 2
+)
+
+// FuzzyResult is a candidate matched by FuzzyRank.
+type FuzzyResult struct {
+	// Candidate is the matched candidate.
+	Candidate string
+	// Score is the match score; higher is better.
+	Score int
+	// Positions are the rune indices in Candidate of the runes matching the query.
+	Positions []int
+}
+
+// fuzzyBonus returns the bonus for matching the rune at index i of runes: runes
+// starting a word, after a separator such as a space, "/", "_" or "-", or at a
+// camelCase hump or the start of a number earn a bonus.
+func fuzzyBonus(runes []rune, i int) int {
+	r := runes[i]
+	if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+		return 0
+	}
+	if i == 0 {
+		return fuzzyBonusBoundary
+	}
+
+	prev := runes[i-1]
+	switch {
+	case !unicode.IsLetter(prev) && !unicode.IsDigit(prev):
+		return fuzzyBonusBoundary
+	case unicode.IsLower(prev) && unicode.IsUpper(r):
+		return fuzzyBonusCamel
+	case !unicode.IsDigit(prev) && unicode.IsDigit(r):
+		return fuzzyBonusCamel
+	}
+	return 0
+}
+
+// fuzzyEqual reports whether the query rune q matches the candidate rune c.
+func fuzzyEqual(q rune, c rune, caseSensitive bool) bool {
+	if caseSensitive {
+		return q == c
+	}
+	return q == c || unicode.ToLower(q) == unicode.ToLower(c)
+}
+
+// FuzzyMatch reports whether all runes of query appear in candidate in order,
+// like the filters of interactive pickers such as fzf, and scores the match. It
+// returns the score, which is higher for better matches, and the rune indices in
+// candidate of the matched runes, which can be passed to HighlightPositions.
+//
+// Matching is smart-case: it ignores case unless query contains an upper case
+// letter. Of all the ways query can match, the one with the highest score is
+// returned. Runes at the start of words, after separators such as " ", "/", "_"
+// and "-", and at camelCase humps earn bonuses, as do runes following another
+// matched rune, while skipped runes between matched runes cost points. An empty
+// query matches every candidate with a score of 0.
+//
+// Example:
+//
+//	FuzzyMatch("fb", "FooBar") // Returns 51, [0 3], true
+//	FuzzyMatch("fb", "Foo")    // Returns 0, nil, false
+func FuzzyMatch(query string, candidate string) (int, []int, bool) {
+	queryRunes := []rune(query)
+	if len(queryRunes) == 0 {
+		return 0, nil, true
+	}
+	runes := []rune(candidate)
+	caseSensitive := strings.IndexFunc(query, unicode.IsUpper) >= 0
+
+	// Check that query is a subsequence before scoring
+	i := 0
+	for _, r := range runes {
+		if i < len(queryRunes) && fuzzyEqual(queryRunes[i], r, caseSensitive) {
+			i++
+		}
+	}
+	if i < len(queryRunes) {
+		return 0, nil, false
+	}
+
+	// scores[i][j] is the best score of matching query[:i+1] with query[i] at
+	// candidate rune j, or noScore; from[i][j] is where query[i-1] matched then,
+	// and runBonus[i][j] is the bonus of the rune starting the run of consecutive
+	// matched runes ending at j.
+	const noScore = -1 << 31
+	scores := make([][]int, len(queryRunes))
+	from := make([][]int, len(queryRunes))
+	runBonus := make([][]int, len(queryRunes))
+	bonuses := make([]int, len(runes))
+	for j := range runes {
+		bonuses[j] = fuzzyBonus(runes, j)
+	}
+
+	for i, q := range queryRunes {
+		scores[i] = make([]int, len(runes))
+		from[i] = make([]int, len(runes))
+		runBonus[i] = make([]int, len(runes))
+
+		// best is the highest scores[i-1][k] + k over k <= j-2. As every skipped
+		// rune after the first costs fuzzyGapExtension, which is 1, the score up to
+		// k after a gap from k to j is best - j + 1 - fuzzyGapStart + fuzzyGapExtension.
+		best, bestAt := noScore, -1
+		for j, r := range runes {
+			scores[i][j] = noScore
+			if i > 0 && j >= 2 && scores[i-1][j-2] != noScore && scores[i-1][j-2]+j-2 > best {
+				best, bestAt = scores[i-1][j-2]+j-2, j-2
+			}
+			if !fuzzyEqual(q, r, caseSensitive) {
+				continue
+			}
+
+			runBonus[i][j] = bonuses[j]
+			if i == 0 {
+				scores[i][j] = fuzzyScoreMatch + bonuses[j]*fuzzyFirstRuneMultiplier
+				continue
+			}
+
+			if best != noScore {
+				scores[i][j] = best - j + 1 - fuzzyGapStart + fuzzyGapExtension + fuzzyScoreMatch + bonuses[j]
+				from[i][j] = bestAt
+			}
+			if j >= 1 && scores[i-1][j-1] != noScore {
+				bonus := max(bonuses[j], runBonus[i-1][j-1], fuzzyBonusConsecutive)
+				if consecutive := scores[i-1][j-1] + fuzzyScoreMatch + bonus; consecutive >= scores[i][j] {
+					scores[i][j] = consecutive
+					from[i][j] = j - 1
+					runBonus[i][j] = max(bonuses[j], runBonus[i-1][j-1])
+				}
+			}
+		}
+	}
+
+	// Pick the best end position and walk back to collect the positions
+	last := len(queryRunes) - 1
+	end := -1
+	for j, score := range scores[last] {
+		if score != noScore && (end < 0 || score > scores[last][end]) {
+			end = j
+		}
+	}
+
+	positions := make([]int, len(queryRunes))
+	for i, j := last, end; i >= 0; i-- {
+		positions[i] = j
+		j = from[i][j]
+	}
+	return scores[last][end], positions, true
+}
+
+// FuzzyRank matches query against every candidate with FuzzyMatch and returns
+// the matching candidates, best first. Candidates with equal scores are ordered
+// by length, shorter first, and then keep their original order.
+//
+// Example:
+//
+//	FuzzyRank("sg", []string{"settings", "s.go", "strings"}) // Returns s.go, strings and settings
+func FuzzyRank(query string, candidates []string) []FuzzyResult {
+	var results []FuzzyResult
+	for _, candidate := range candidates {
+		if score, positions, ok := FuzzyMatch(query, candidate); ok {
+			results = append(results, FuzzyResult{Candidate: candidate, Score: score, Positions: positions})
+		}
+	}
+
+	slices.SortStableFunc(results, func(a, b FuzzyResult) int {
+		if a.Score != b.Score {
+			return b.Score - a.Score
+		}
+		return utf8.RuneCountInString(a.Candidate) - utf8.RuneCountInString(b.Candidate)
+	})
+	return results
+}
+
+// HighlightPositions wraps the runes of s at the given rune indices with left
+// and right, merging adjacent runes into one highlight. It is meant for the
+// positions returned by FuzzyMatch. Indices out of range are ignored.
+//
+// Example:
+//
+//	HighlightPositions("FooBar", []int{0, 3, 4}, "[", "]") // Returns "[F]oo[Ba]r"
+func HighlightPositions(s string, positions []int, left string, right string) string {
+	if len(positions) == 0 {
+		return s
+	}
+	marked := make(map[int]bool, len(positions))
+	for _, i := range positions {
+		marked[i] = true
+	}
+
+	var builder strings.Builder
+	inside := false
+	i := 0
+	for _, r := range s {
+		if marked[i] != inside {
+			if inside {
+				builder.WriteString(right)
+			} else {
+				builder.WriteString(left)
+			}
+			inside = !inside
+		}
+		builder.WriteRune(r)
+		i++
+	}
+	if inside {
+		builder.WriteString(right)
+	}
+	return builder.String()
+}
@@ -0,0 +1,139 @@
+package s
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		candidate string
+		matched   bool
+		positions []int
+	}{
+		{"camel case", "fb", "FooBar", true, []int{0, 3}},
+		{"snake case", "fb", "foo_bar", true, []int{0, 4}},
+		{"no match", "fb", "Foo", false, nil},
+		{"out of order", "ba", "abc", false, nil},
+		{"prefers consecutive", "abc", "a_b_abc", true, []int{4, 5, 6}},
+		{"prefers boundary", "b", "abc b", true, []int{4}},
+		{"prefers later consecutive", "ab", "xaab", true, []int{2, 3}},
+		{"smart case lower", "foo", "FOO", true, []int{0, 1, 2}},
+		{"smart case upper", "Fb", "FooBar", false, nil},
+		{"smart case upper match", "FB", "FooBar", true, []int{0, 3}},
+		{"empty query", "", "anything", true, nil},
+		{"empty candidate", "a", "", false, nil},
+		{"unicode", "世界", "你好 世界", true, []int{3, 4}},
+		{"path", "sgo", "src/s.go", true, []int{4, 6, 7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, positions, matched := FuzzyMatch(tt.query, tt.candidate)
+			if matched != tt.matched || !reflect.DeepEqual(positions, tt.positions) {
+				t.Errorf("FuzzyMatch(%q, %q) = %d, %v, %v; want positions %v, %v",
+					tt.query, tt.candidate, score, positions, matched, tt.positions, tt.matched)
+			}
+			if !matched && score != 0 {
+				t.Errorf("FuzzyMatch(%q, %q) score = %d; want 0 without a match", tt.query, tt.candidate, score)
+			}
+		})
+	}
+}
+
+func TestFuzzyMatchScores(t *testing.T) {
+	// Each pair lists a better candidate before a worse one for the same query
+	tests := []struct {
+		query  string
+		better string
+		worse  string
+	}{
+		{"fb", "FooBar", "fooxbar"},
+		{"fb", "foo_bar", "foobar"},
+		{"abc", "abcxx", "axbxc"},
+		{"main", "main.go", "domain.go"},
+		{"sg", "s.go", "settings"},
+		{"gr", "grep.go", "tiger"},
+	}
+
+	for _, tt := range tests {
+		better, _, ok1 := FuzzyMatch(tt.query, tt.better)
+		worse, _, ok2 := FuzzyMatch(tt.query, tt.worse)
+		if !ok1 || !ok2 || better <= worse {
+			t.Errorf("FuzzyMatch(%q): %q scored %d, %q scored %d; want the first higher",
+				tt.query, tt.better, better, tt.worse, worse)
+		}
+	}
+}
+
+func TestFuzzyMatchLongCandidate(t *testing.T) {
+	candidate := strings.Repeat("x", 10000) + "needle"
+	if _, positions, ok := FuzzyMatch("ndl", candidate); !ok || len(positions) != 3 || positions[0] != 10000 {
+		t.Errorf("FuzzyMatch on long candidate = %v, %v", positions, ok)
+	}
+}
+
+func TestFuzzyRank(t *testing.T) {
+	candidates := []string{"settings.json", "strings.go", "s.go", "README.md", "sg", "gs"}
+	results := FuzzyRank("sg", candidates)
+
+	var got []string
+	for _, result := range results {
+		got = append(got, result.Candidate)
+	}
+	want := []string{"sg", "s.go", "strings.go", "settings.json"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FuzzyRank = %q; want %q", got, want)
+	}
+	for i := 1; i < len(results); i++ {
+		if results[i].Score > results[i-1].Score {
+			t.Errorf("FuzzyRank results not sorted by score: %+v", results)
+		}
+	}
+
+	// Equal scores are ordered by length, then keep their order
+	tied := FuzzyRank("a", []string{"abc", "ab", "axx", "a"})
+	got = got[:0]
+	for _, result := range tied {
+		got = append(got, result.Candidate)
+	}
+	want = []string{"a", "ab", "abc", "axx"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FuzzyRank ties = %q; want %q", got, want)
+	}
+
+	if results := FuzzyRank("zz", candidates); len(results) != 0 {
+		t.Errorf("FuzzyRank without matches = %+v; want none", results)
+	}
+	if results := FuzzyRank("", candidates); len(results) != len(candidates) || results[0].Candidate != "sg" {
+		t.Errorf("FuzzyRank with empty query = %+v; want all candidates, shortest first", results)
+	}
+}
+
+func TestHighlightPositions(t *testing.T) {
+	tests := []struct {
+		s         string
+		positions []int
+		expected  string
+	}{
+		{"FooBar", []int{0, 3, 4}, "[F]oo[Ba]r"},
+		{"FooBar", nil, "FooBar"},
+		{"FooBar", []int{5}, "FooBa[r]"},
+		{"FooBar", []int{-1, 10}, "FooBar"},
+		{"你好世界", []int{1, 2}, "你[好世]界"},
+	}
+
+	for _, tt := range tests {
+		if got := HighlightPositions(tt.s, tt.positions, "[", "]"); got != tt.expected {
+			t.Errorf("HighlightPositions(%q, %v) = %q; want %q", tt.s, tt.positions, got, tt.expected)
+		}
+	}
+
+	_, positions, _ := FuzzyMatch("fb", "FooBar")
+	if got := HighlightPositions("FooBar", positions, "<", ">"); got != "<F>oo<B>ar" {
+		t.Errorf("HighlightPositions with FuzzyMatch positions = %q", got)
+	}
+}
@@ -0,0 +1,193 @@
+//go:build ignore
+
+// This program generates tables.go from the Unicode Character Database files
+// checked into ucd/. Run it with "go generate" after updating those files.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// source selects the properties to pick up from one UCD file, mapping each
+// property value to the Go constant it becomes in the generated table.
+type source struct {
+	file  string
+	props map[string]string
+}
+
+type table struct {
+	name    string
+	comment string
+	sources []source
+}
+
+var tables = []table{
+	{
+		name:    "graphemeBreakRanges",
+		comment: "graphemeBreakRanges maps code points to their Grapheme_Cluster_Break property,\n// with Extended_Pictographic folded in as an additional value.",
+		sources: []source{
+			{
+				file: "GraphemeBreakProperty.txt",
+				props: map[string]string{
+					"CR":                 "gbCR",
+					"LF":                 "gbLF",
+					"Control":            "gbControl",
+					"Extend":             "gbExtend",
+					"ZWJ":                "gbZWJ",
+					"Regional_Indicator": "gbRegionalIndicator",
+					"Prepend":            "gbPrepend",
+					"SpacingMark":        "gbSpacingMark",
+					"L":                  "gbL",
+					"V":                  "gbV",
+					"T":                  "gbT",
+					"LV":                 "gbLV",
+					"LVT":                "gbLVT",
+				},
+			},
+			{
+				file: "emoji-data.txt",
+				props: map[string]string{
+					"Extended_Pictographic": "gbExtendedPictographic",
+				},
+			},
+		},
+	},
+	{
+		name:    "eastAsianWidthRanges",
+		comment: "eastAsianWidthRanges lists the code points whose East_Asian_Width is Wide or Fullwidth.",
+		sources: []source{
+			{
+				file: "EastAsianWidth.txt",
+				props: map[string]string{
+					"W": "eaWide",
+					"F": "eaFullwidth",
+				},
+			},
+		},
+	},
+	{
+		name:    "emojiPresentationRanges",
+		comment: "emojiPresentationRanges lists the code points with Emoji_Presentation=Yes.",
+		sources: []source{
+			{
+				file: "emoji-data.txt",
+				props: map[string]string{
+					"Emoji_Presentation": "emojiPresentation",
+				},
+			},
+		},
+	},
+}
+
+type entry struct {
+	lo, hi rune
+	prop   string
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("gen_tables: ")
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen_tables.go from the files in ucd/. DO NOT EDIT.\n\n")
+	buf.WriteString("package s\n")
+
+	for _, t := range tables {
+		var entries []entry
+		for _, src := range t.sources {
+			parsed, err := parseFile(filepath.Join("ucd", src.file), src.props)
+			if err != nil {
+				log.Fatal(err)
+			}
+			entries = append(entries, parsed...)
+		}
+
+		sort.Slice(entries, func(i, j int) bool { return entries[i].lo < entries[j].lo })
+		var merged []entry
+		for _, e := range entries {
+			if n := len(merged); n > 0 {
+				last := &merged[n-1]
+				if e.lo <= last.hi {
+					log.Fatalf("%s: overlapping ranges %04X..%04X and %04X..%04X",
+						t.name, last.lo, last.hi, e.lo, e.hi)
+				}
+				// Merge adjacent ranges with the same property
+				if e.lo == last.hi+1 && e.prop == last.prop {
+					last.hi = e.hi
+					continue
+				}
+			}
+			merged = append(merged, e)
+		}
+		entries = merged
+
+		fmt.Fprintf(&buf, "\n// %s\nvar %s = []propertyRange{\n", t.comment, t.name)
+		for _, e := range entries {
+			fmt.Fprintf(&buf, "\t{0x%04X, 0x%04X, %s},\n", e.lo, e.hi, e.prop)
+		}
+		buf.WriteString("}\n")
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("tables.go", src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parseFile reads the "XXXX..YYYY ; Property # comment" lines of a UCD file and
+// keeps those whose property appears in props.
+func parseFile(path string, props map[string]string) ([]entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []entry
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		fields := strings.Split(line, ";")
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: malformed line", path, lineNo)
+		}
+		prop, ok := props[strings.TrimSpace(fields[1])]
+		if !ok {
+			continue
+		}
+
+		lo, hi, found := strings.Cut(strings.TrimSpace(fields[0]), "..")
+		if !found {
+			hi = lo
+		}
+		first, err := strconv.ParseUint(lo, 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, lineNo, err)
+		}
+		last, err := strconv.ParseUint(hi, 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, lineNo, err)
+		}
+		entries = append(entries, entry{rune(first), rune(last), prop})
+	}
+	return entries, scanner.Err()
+}
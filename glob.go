@@ -0,0 +1,278 @@
+package s
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// GlobOptions configures GlobToRegexWithOptions, GlobMatchWithOptions and GlobMatchE.
+type GlobOptions struct {
+	// Path makes the glob path-aware: "*", "?" and character classes do not
+	// match "/", and "**" as a whole path segment matches any number of
+	// directories, including none, so "build/**/tmp" matches "build/tmp" and
+	// "build/a/b/tmp". Both the glob and the matched path are converted with
+	// ToLinuxPathSeparator first, so Windows paths match too; backslashes are
+	// therefore separators rather than escapes in this mode.
+	Path bool
+}
+
+// globTranslator converts a glob to a regular expression.
+type globTranslator struct {
+	glob  string
+	runes []rune
+	pos   int
+	opts  GlobOptions
+}
+
+// errorf returns an error wrapping ErrInvalidGlob that describes a problem with the glob.
+func (t *globTranslator) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w %q: %s", ErrInvalidGlob, t.glob, fmt.Sprintf(format, args...))
+}
+
+// translate converts the glob from the current position up to its end or, inside
+// braces, up to the next "," or "}" at the same level, which is left unread.
+func (t *globTranslator) translate(inBraces bool) (string, error) {
+	var builder strings.Builder
+	for t.pos < len(t.runes) {
+		r := t.runes[t.pos]
+		switch {
+		case r == '*':
+			builder.WriteString(t.stars())
+		case r == '?':
+			t.pos++
+			if t.opts.Path {
+				builder.WriteString(`[^/]`)
+			} else {
+				builder.WriteString(`(?s:.)`)
+			}
+		case r == '[':
+			class, err := t.class()
+			if err != nil {
+				return "", err
+			}
+			builder.WriteString(class)
+		case r == '{':
+			alternatives, err := t.braces()
+			if err != nil {
+				return "", err
+			}
+			builder.WriteString(alternatives)
+		case (r == ',' || r == '}') && inBraces:
+			return builder.String(), nil
+		case r == '\\' && !t.opts.Path && t.pos+1 < len(t.runes):
+			// An escaped character stands for itself
+			builder.WriteString(regexp.QuoteMeta(string(t.runes[t.pos+1])))
+			t.pos += 2
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(r)))
+			t.pos++
+		}
+	}
+	return builder.String(), nil
+}
+
+// stars translates a run of "*" at the current position.
+func (t *globTranslator) stars() string {
+	start := t.pos
+	for t.pos < len(t.runes) && t.runes[t.pos] == '*' {
+		t.pos++
+	}
+	if !t.opts.Path {
+		return `(?s:.*)`
+	}
+
+	// "**" only spans directories when it makes up a whole path segment
+	segmentStart := start == 0 || strings.ContainsRune("/{,", t.runes[start-1])
+	segmentEnd := t.pos == len(t.runes) || strings.ContainsRune("/},", t.runes[t.pos])
+	if t.pos-start < 2 || !segmentStart || !segmentEnd {
+		return `[^/]*`
+	}
+	if t.pos < len(t.runes) && t.runes[t.pos] == '/' {
+		// "**/" matches any number of directories, including none
+		t.pos++
+		return `(?s:.*/)?`
+	}
+	return `(?s:.*)`
+}
+
+// class translates the character class at the current position, such as "[a-z]",
+// "[!0-9]" or "[^.]".
+func (t *globTranslator) class() (string, error) {
+	start := t.pos
+	t.pos++
+
+	var builder strings.Builder
+	builder.WriteString("[")
+	negated := t.pos < len(t.runes) && (t.runes[t.pos] == '!' || t.runes[t.pos] == '^')
+	if negated {
+		builder.WriteString("^")
+		if t.opts.Path {
+			builder.WriteString("/")
+		}
+		t.pos++
+	}
+
+	// In path mode "/" is excluded from negated classes above and left out of
+	// the others here, splitting ranges that span it
+	empty := true
+	writeRange := func(lo, hi rune) {
+		empty = false
+		if lo == hi {
+			builder.WriteString(classRune(lo))
+		} else {
+			builder.WriteString(classRune(lo) + "-" + classRune(hi))
+		}
+	}
+	addRange := func(lo, hi rune) {
+		if !t.opts.Path || negated || lo > '/' || '/' > hi {
+			writeRange(lo, hi)
+			return
+		}
+		if lo < '/' {
+			writeRange(lo, '/'-1)
+		}
+		if hi > '/' {
+			writeRange('/'+1, hi)
+		}
+	}
+
+	first := true
+	for {
+		if t.pos >= len(t.runes) {
+			return "", t.errorf("unterminated character class at position %d", start)
+		}
+		r := t.runes[t.pos]
+		if r == ']' && !first {
+			t.pos++
+			break
+		}
+		first = false
+
+		if r == '\\' && !t.opts.Path && t.pos+1 < len(t.runes) {
+			t.pos++
+			r = t.runes[t.pos]
+		}
+		t.pos++
+
+		// A range such as "a-z", unless the "-" is the last character of the class
+		if t.pos+1 < len(t.runes) && t.runes[t.pos] == '-' && t.runes[t.pos+1] != ']' {
+			hi := t.runes[t.pos+1]
+			if hi < r {
+				return "", t.errorf("invalid character range %c-%c", r, hi)
+			}
+			t.pos += 2
+			addRange(r, hi)
+			continue
+		}
+		addRange(r, r)
+	}
+
+	if empty && !negated {
+		// A class of nothing but "/" matches no character in path mode
+		return `[^\x00-\x{10FFFF}]`, nil
+	}
+	builder.WriteString("]")
+	return builder.String(), nil
+}
+
+// classRune escapes r for use inside a regular expression character class.
+func classRune(r rune) string {
+	if strings.ContainsRune(`\]-^[`, r) {
+		return `\` + string(r)
+	}
+	return string(r)
+}
+
+// braces translates the brace expansion at the current position, such as
+// "{a,b}", into an alternation. Braces may be nested.
+func (t *globTranslator) braces() (string, error) {
+	start := t.pos
+	t.pos++
+
+	var alternatives []string
+	for {
+		alternative, err := t.translate(true)
+		if err != nil {
+			return "", err
+		}
+		alternatives = append(alternatives, alternative)
+
+		if t.pos >= len(t.runes) {
+			return "", t.errorf("unterminated brace expansion at position %d", start)
+		}
+		closing := t.runes[t.pos] == '}'
+		t.pos++
+		if closing {
+			break
+		}
+	}
+	return "(?:" + strings.Join(alternatives, "|") + ")", nil
+}
+
+// GlobToRegex converts a shell glob into an equivalent regular expression, so that
+// globs can be used with Grep, Highlight and the other regex functions. The
+// supported syntax is "*" for any run of characters and "?" for any one
+// character, both including newlines, character classes such as "[a-z]" and
+// "[!0-9]", brace expansion such as "{jpg,png}" and "\" to escape the next
+// character. The regex is not anchored; wrap it in "\A(?:" and ")\z" to match
+// whole strings, as GlobMatch does. An error wrapping ErrInvalidGlob is returned
+// for malformed globs.
+//
+// Example:
+//
+//	GlobToRegex("file?.[ch]") // Returns `file(?s:.)\.[ch]`
+func GlobToRegex(glob string) (string, error) {
+	return GlobToRegexWithOptions(glob, GlobOptions{})
+}
+
+// GlobToRegexWithOptions is like GlobToRegex but accepts options, such as
+// path-aware matching.
+func GlobToRegexWithOptions(glob string, opts GlobOptions) (string, error) {
+	if opts.Path {
+		glob = ToLinuxPathSeparator(glob)
+	}
+	t := &globTranslator{glob: glob, runes: []rune(glob), opts: opts}
+	return t.translate(false)
+}
+
+// GlobMatch reports whether all of s matches the shell glob pattern. See
+// GlobToRegex for the supported syntax. In this mode "*" also matches "/"; use
+// GlobMatchWithOptions for path-aware matching. If the glob is malformed, false
+// is returned.
+//
+// Example:
+//
+//	GlobMatch("server.log", "*.{log,txt}") // Returns true
+func GlobMatch(s string, pattern string) bool {
+	return GlobMatchWithOptions(s, pattern, GlobOptions{})
+}
+
+// GlobMatchWithOptions is like GlobMatch but accepts options, such as path-aware
+// matching. If the glob is malformed, false is returned.
+//
+// Example:
+//
+//	GlobMatchWithOptions(`build\a\b\tmp`, "build/**/tmp", GlobOptions{Path: true}) // Returns true
+func GlobMatchWithOptions(s string, pattern string, opts GlobOptions) bool {
+	matched, _ := GlobMatchE(s, pattern, opts)
+	return matched
+}
+
+// GlobMatchE is like GlobMatchWithOptions but returns an error wrapping
+// ErrInvalidGlob if the glob is malformed.
+func GlobMatchE(s string, pattern string, opts GlobOptions) (bool, error) {
+	regex, err := GlobToRegexWithOptions(pattern, opts)
+	if err != nil {
+		return false, err
+	}
+	p, err := compilePattern(`\A(?:` + regex + `)\z`)
+	if err != nil {
+		return false, err
+	}
+
+	if opts.Path {
+		s = ToLinuxPathSeparator(s)
+	}
+	return p.IsMatch(s), nil
+}
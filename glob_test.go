@@ -0,0 +1,145 @@
+package s
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		s        string
+		pattern  string
+		expected bool
+	}{
+		{"server.log", "*.log", true},
+		{"logs/server.log", "*.log", true},
+		{"server.log.1", "*.log", false},
+		{"file1.c", "file?.[ch]", true},
+		{"file2.h", "file?.[ch]", true},
+		{"file10.c", "file?.[ch]", false},
+		{"file1.o", "file?.[ch]", false},
+		{"photo.png", "*.{jpg,png}", true},
+		{"photo.gif", "*.{jpg,png}", false},
+		{"a.tar.gz", "*.{zip,tar.{gz,bz2}}", true},
+		{"a.tar.xz", "*.{zip,tar.{gz,bz2}}", false},
+		{"x", "[!0-9]", true},
+		{"5", "[!0-9]", false},
+		{"5", "[^0-9]", false},
+		{"]", "[]]", true},
+		{"-", "[a-]", true},
+		{"b", "[a-c]", true},
+		{"*", `\*`, true},
+		{"a", `\*`, false},
+		{"a+b(c)", "a+b(c)", true},
+		{"a.b", "a?b", true},
+		{"你好.txt", "??.txt", true},
+		{"", "", true},
+		{"", "*", true},
+		{"a", "", false},
+		{"a,b}", "a,b}", true},
+		{"a\nb.log", "*.log", true},
+		{"a\nb", "a?b", true},
+		{"a\nb", "a[\n]b", true},
+	}
+
+	for _, tt := range tests {
+		if got := GlobMatch(tt.s, tt.pattern); got != tt.expected {
+			t.Errorf("GlobMatch(%q, %q) = %v; want %v", tt.s, tt.pattern, got, tt.expected)
+		}
+	}
+}
+
+func TestGlobMatchPath(t *testing.T) {
+	tests := []struct {
+		s        string
+		pattern  string
+		expected bool
+	}{
+		{"server.log", "*.log", true},
+		{"logs/server.log", "*.log", false},
+		{"logs/server.log", "**/*.log", true},
+		{"server.log", "**/*.log", true},
+		{"build/tmp", "build/**/tmp", true},
+		{"build/a/tmp", "build/**/tmp", true},
+		{"build/a/b/tmp", "build/**/tmp", true},
+		{"build/atmp", "build/**/tmp", false},
+		{"build/a/b", "build/**", true},
+		{"other/a", "build/**", false},
+		{"a/b", "a?b", false},
+		{"a/b", "a[!x]b", false},
+		{"a/b", "a**b", false},
+		{"ab", "a**b", true},
+		{"a/b", "a[/]b", false},
+		{"a/b", "a[-/]b", false},
+		{"a-b", "a[-/]b", true},
+		{"a/b", "a[!-0]b", false},
+		{"a/b", "a[.-0]b", false},
+		{"a.b", "a[.-0]b", true},
+		{"a0b", "a[.-0]b", true},
+		{"a\nb/c", "a?b/*", true},
+		{"src/main.go", "{src,lib}/**/*.go", true},
+		{"lib/x/y.go", "{src,lib}/**/*.go", true},
+		{"doc/main.go", "{src,lib}/**/*.go", false},
+
+		// Windows paths
+		{`build\a\b\tmp`, "build/**/tmp", true},
+		{`C:\logs\server.log`, "C:/logs/*.log", true},
+		{`C:\logs\server.log`, `C:\logs\*.log`, true},
+		{`logs\\server.log`, "logs/*.log", true},
+	}
+
+	for _, tt := range tests {
+		if got := GlobMatchWithOptions(tt.s, tt.pattern, GlobOptions{Path: true}); got != tt.expected {
+			t.Errorf("GlobMatchWithOptions(%q, %q, Path) = %v; want %v", tt.s, tt.pattern, got, tt.expected)
+		}
+	}
+}
+
+func TestGlobToRegex(t *testing.T) {
+	tests := []struct {
+		glob     string
+		path     bool
+		expected string
+	}{
+		{"file?.[ch]", false, `file(?s:.)\.[ch]`},
+		{"*.{jpg,png}", false, `(?s:.*)\.(?:jpg|png)`},
+		{"[!a-z]", false, `[^a-z]`},
+		{"[!a]", true, `[^/a]`},
+		{"a/**/b", true, `a/(?s:.*/)?b`},
+		{"*.go", true, `[^/]*\.go`},
+		{`a\b`, true, `a/b`},
+		{"[.-0]", true, `[.0]`},
+		{"[/]", true, `[^\x00-\x{10FFFF}]`},
+	}
+
+	for _, tt := range tests {
+		got, err := GlobToRegexWithOptions(tt.glob, GlobOptions{Path: tt.path})
+		if err != nil || got != tt.expected {
+			t.Errorf("GlobToRegexWithOptions(%q, Path: %v) = %q, %v; want %q", tt.glob, tt.path, got, err, tt.expected)
+		}
+	}
+
+	// The regex works with the other regex functions
+	regex, err := GlobToRegex("err*[0-9]")
+	if err != nil {
+		t.Fatalf("GlobToRegex error: %v", err)
+	}
+	if got := Highlight("see err42 here", regex, "[", "]"); got != "see [err42] here" {
+		t.Errorf("Highlight with glob regex = %q", got)
+	}
+}
+
+func TestGlobErrors(t *testing.T) {
+	for _, glob := range []string{"[abc", "[", "{a,b", "a{b,{c}", "[z-a]"} {
+		if _, err := GlobToRegex(glob); !errors.Is(err, ErrInvalidGlob) {
+			t.Errorf("GlobToRegex(%q) error = %v; want ErrInvalidGlob", glob, err)
+		}
+		matched, err := GlobMatchE("abc", glob, GlobOptions{})
+		if matched || !errors.Is(err, ErrInvalidGlob) {
+			t.Errorf("GlobMatchE(%q) = %v, %v; want false and ErrInvalidGlob", glob, matched, err)
+		}
+		if GlobMatch("abc", glob) {
+			t.Errorf("GlobMatch(%q) = true; want false", glob)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package s
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+//go:generate go run gen_tables.go
+
+// propertyRange assigns a Unicode property value to the code points lo..hi.
+type propertyRange struct {
+	lo, hi rune
+	prop   uint8
+}
+
+// lookupProperty returns the property value of r in a sorted table, or 0 if
+// r is not listed.
+func lookupProperty(table []propertyRange, r rune) uint8 {
+	i := sort.Search(len(table), func(i int) bool { return table[i].hi >= r })
+	if i < len(table) && table[i].lo <= r {
+		return table[i].prop
+	}
+	return 0
+}
+
+// Grapheme_Cluster_Break property values (UAX #29), plus Extended_Pictographic.
+const (
+	gbOther uint8 = iota
+	gbCR
+	gbLF
+	gbControl
+	gbExtend
+	gbZWJ
+	gbRegionalIndicator
+	gbPrepend
+	gbSpacingMark
+	gbL
+	gbV
+	gbT
+	gbLV
+	gbLVT
+	gbExtendedPictographic
+)
+
+// nextGrapheme returns the length in bytes of the first extended grapheme
+// cluster in s, following the rules of Unicode Standard Annex #29.
+func nextGrapheme(s string) int {
+	r, pos := utf8.DecodeRuneInString(s)
+	if pos == 0 {
+		return 0
+	}
+
+	prev := lookupProperty(graphemeBreakRanges, r)
+
+	// pict is set while we're inside an Extended_Pictographic Extend* sequence,
+	// zwjPict when such a sequence was followed by a ZWJ (GB11).
+	pict := prev == gbExtendedPictographic
+	zwjPict := false
+
+	// Number of consecutive regional indicators ending at prev (GB12, GB13)
+	riCount := 0
+	if prev == gbRegionalIndicator {
+		riCount = 1
+	}
+
+	for pos < len(s) {
+		r, size := utf8.DecodeRuneInString(s[pos:])
+		cur := lookupProperty(graphemeBreakRanges, r)
+		if graphemeBreak(prev, cur, zwjPict, riCount) {
+			break
+		}
+
+		switch cur {
+		case gbExtendedPictographic:
+			pict = true
+		case gbExtend:
+			// Extend keeps an emoji sequence open
+		case gbZWJ:
+			zwjPict = pict
+			pict = false
+		default:
+			pict = false
+		}
+		if cur != gbZWJ {
+			zwjPict = false
+		}
+
+		if cur == gbRegionalIndicator {
+			riCount++
+		} else {
+			riCount = 0
+		}
+
+		prev = cur
+		pos += size
+	}
+
+	return pos
+}
+
+// graphemeBreak reports whether there is a grapheme cluster boundary between
+// two code points with the properties prev and cur.
+func graphemeBreak(prev, cur uint8, zwjPict bool, riCount int) bool {
+	switch {
+	case prev == gbCR && cur == gbLF: // GB3
+		return false
+	case prev == gbControl || prev == gbCR || prev == gbLF: // GB4
+		return true
+	case cur == gbControl || cur == gbCR || cur == gbLF: // GB5
+		return true
+	case prev == gbL && (cur == gbL || cur == gbV || cur == gbLV || cur == gbLVT): // GB6
+		return false
+	case (prev == gbLV || prev == gbV) && (cur == gbV || cur == gbT): // GB7
+		return false
+	case (prev == gbLVT || prev == gbT) && cur == gbT: // GB8
+		return false
+	case cur == gbExtend || cur == gbZWJ: // GB9
+		return false
+	case cur == gbSpacingMark: // GB9a
+		return false
+	case prev == gbPrepend: // GB9b
+		return false
+	case prev == gbZWJ && zwjPict && cur == gbExtendedPictographic: // GB11
+		return false
+	case prev == gbRegionalIndicator && cur == gbRegionalIndicator: // GB12, GB13
+		return riCount%2 == 0
+	}
+	return true // GB999
+}
+
+// splitGraphemes splits s into its extended grapheme clusters.
+func splitGraphemes(s string) []string {
+	var graphemes []string
+	for len(s) > 0 {
+		n := nextGrapheme(s)
+		graphemes = append(graphemes, s[:n])
+		s = s[n:]
+	}
+	return graphemes
+}
+
+// LenGrapheme returns the number of user-perceived characters (extended grapheme
+// clusters) in s, so "é", "🇯🇵" and "👨‍👩‍👧" each count as one.
+func LenGrapheme(s string) int {
+	count := 0
+	for len(s) > 0 {
+		s = s[nextGrapheme(s):]
+		count++
+	}
+	return count
+}
+
+func LengthGrapheme(s string) int {
+	return LenGrapheme(s)
+}
+
+// EachGrapheme is like EachChar but calls callback once per extended grapheme
+// cluster instead of once per rune.
+func EachGrapheme(s string, callback func(grapheme string, index int)) {
+	for i := 0; len(s) > 0; i++ {
+		n := nextGrapheme(s)
+		callback(s[:n], i)
+		s = s[n:]
+	}
+}
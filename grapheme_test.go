@@ -0,0 +1,163 @@
+package s
+
+import (
+	"bufio"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// loadGraphemeBreakTest parses testdata/GraphemeBreakTest.txt into input strings
+// and their expected grapheme clusters.
+func loadGraphemeBreakTest(t *testing.T) (inputs []string, expected [][]string) {
+	t.Helper()
+
+	f, err := os.Open("testdata/GraphemeBreakTest.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		var input strings.Builder
+		var clusters []string
+		var cluster strings.Builder
+		for _, field := range strings.Fields(line) {
+			switch field {
+			case "÷":
+				if cluster.Len() > 0 {
+					clusters = append(clusters, cluster.String())
+					cluster.Reset()
+				}
+			case "×":
+			default:
+				cp, err := strconv.ParseUint(field, 16, 32)
+				if err != nil {
+					t.Fatalf("GraphemeBreakTest.txt:%d: %v", lineNo, err)
+				}
+				input.WriteRune(rune(cp))
+				cluster.WriteRune(rune(cp))
+			}
+		}
+		inputs = append(inputs, input.String())
+		expected = append(expected, clusters)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return inputs, expected
+}
+
+func TestGraphemeBreakTest(t *testing.T) {
+	inputs, expected := loadGraphemeBreakTest(t)
+	if len(inputs) == 0 {
+		t.Fatal("no test cases in GraphemeBreakTest.txt")
+	}
+
+	for i, input := range inputs {
+		if got := splitGraphemes(input); !reflect.DeepEqual(got, expected[i]) {
+			t.Errorf("splitGraphemes(%+q) = %+q; want %+q", input, got, expected[i])
+		}
+		if got := LenGrapheme(input); got != len(expected[i]) {
+			t.Errorf("LenGrapheme(%+q) = %d; want %d", input, got, len(expected[i]))
+		}
+	}
+}
+
+func TestLenGrapheme(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{"", 0},
+		{"hello", 5},
+		{"e\u0301", 1},
+		{"cafe\u0301", 4},
+		{"🇯🇵🇺🇸", 2},
+		{"👨‍👩‍👧", 1},
+		{"👍🏽", 1},
+		{"\r\n", 1},
+		{"\n\r", 2},
+		{"한국어", 3},
+		{"각", 1},
+		{"你好世界", 4},
+	}
+
+	for _, test := range tests {
+		if result := LenGrapheme(test.input); result != test.expected {
+			t.Errorf("LenGrapheme(%q) = %d; want %d", test.input, result, test.expected)
+		}
+		if result := LengthGrapheme(test.input); result != test.expected {
+			t.Errorf("LengthGrapheme(%q) = %d; want %d", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestEachGrapheme(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"", nil},
+		{"abc", []string{"a", "b", "c"}},
+		{"cafe\u0301!", []string{"c", "a", "f", "e\u0301", "!"}},
+		{"a👨‍👩‍👧b", []string{"a", "👨‍👩‍👧", "b"}},
+		{"🇯🇵🇺", []string{"🇯🇵", "🇺"}},
+	}
+
+	for _, test := range tests {
+		var graphemes []string
+		var indexes []int
+		EachGrapheme(test.input, func(grapheme string, index int) {
+			graphemes = append(graphemes, grapheme)
+			indexes = append(indexes, index)
+		})
+		if !reflect.DeepEqual(graphemes, test.expected) {
+			t.Errorf("EachGrapheme(%q) graphemes = %q; want %q", test.input, graphemes, test.expected)
+		}
+		for i, index := range indexes {
+			if index != i {
+				t.Errorf("EachGrapheme(%q) index %d = %d", test.input, i, index)
+			}
+		}
+	}
+}
+
+func TestGraphemePadding(t *testing.T) {
+	tests := []struct {
+		name     string
+		fn       func(string, string, int) string
+		s        string
+		padStr   string
+		length   int
+		expected string
+	}{
+		{"LeftPadGrapheme_Combining", LeftPadGrapheme, "cafe\u0301", " ", 6, "  cafe\u0301"},
+		{"LeftPadGrapheme_Flag", LeftPadGrapheme, "🇯🇵", "-", 3, "--🇯🇵"},
+		{"LeftPadGrapheme_GraphemePad", LeftPadGrapheme, "abc", "e\u0301", 5, "e\u0301e\u0301abc"},
+		{"LeftPadGrapheme_NoChange", LeftPadGrapheme, "👨‍👩‍👧", " ", 1, "👨‍👩‍👧"},
+		{"RightPadGrapheme_Combining", RightPadGrapheme, "cafe\u0301", ".", 6, "cafe\u0301.."},
+		{"RightPadGrapheme_Family", RightPadGrapheme, "👨‍👩‍👧", "XY", 4, "👨‍👩‍👧XYX"},
+		{"RightPadGrapheme_EmptyPad", RightPadGrapheme, "abc", "", 5, "abc"},
+		{"PadGrapheme_Combining", PadGrapheme, "cafe\u0301", " ", 8, "  cafe\u0301  "},
+		{"PadGrapheme_Flag", PadGrapheme, "🇯🇵", "*", 4, "**🇯🇵*"},
+		{"PadGrapheme_MultiChar", PadGrapheme, "abc", "XY", 10, "XYXYabcXYX"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.fn(tt.s, tt.padStr, tt.length)
+			if got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
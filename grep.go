@@ -0,0 +1,197 @@
+package s
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DefaultGroupSeparator is printed between non-adjacent groups of lines by
+// GrepLinesAsString when context lines are requested, as grep does.
+const DefaultGroupSeparator = "--"
+
+// GrepOptions configures GrepLines and GrepLinesAsString. The zero value selects
+// matching lines only, without context, numbers or offsets.
+type GrepOptions struct {
+	// Before and After set the number of context lines shown before and after
+	// each matching line, like grep -B and -A. Set both to the same value for
+	// grep -C.
+	Before, After int
+	// MaxCount stops after that many matching lines, like grep -m. The context
+	// after the last match is still included. Zero means no limit.
+	MaxCount int
+	// LineNumbers prefixes each formatted line with its 1-based line number.
+	LineNumbers bool
+	// ByteOffsets prefixes each formatted line with the byte offset of its start.
+	ByteOffsets bool
+	// GroupSeparator separates non-adjacent groups of lines in the formatted
+	// output when context lines are requested. It defaults to DefaultGroupSeparator.
+	GroupSeparator string
+	// NoGroupSeparator omits the separator between groups of lines.
+	NoGroupSeparator bool
+}
+
+// LineMatch is a line selected by GrepLines, either because it matched or
+// because it is context around a matching line.
+type LineMatch struct {
+	// Text is the line without its line ending.
+	Text string
+	// LineNumber is the 1-based number of the line.
+	LineNumber int
+	// ByteOffset is the offset in bytes of the start of the line.
+	ByteOffset int
+	// IsContext is true for context lines and false for matching lines.
+	IsContext bool
+}
+
+// GrepLines returns the lines of s that match the pattern along with the
+// requested context lines, in order and without duplicates. Lines are split on
+// "\n" with a trailing "\r" removed, so CRLF input is handled, and empty lines
+// are kept so that line numbers stay accurate.
+func (p *Pattern) GrepLines(s string, opts GrepOptions) []LineMatch {
+	if s == "" {
+		return []LineMatch{}
+	}
+
+	before := max(opts.Before, 0)
+	after := max(opts.After, 0)
+
+	result := []LineMatch{}
+	lastEmitted := -1
+	pendingAfter := 0
+	matched := 0
+
+	lines := strings.SplitAfter(strings.TrimSuffix(s, "\n"), "\n")
+	offsets := make([]int, len(lines))
+	offset := 0
+	for i, line := range lines {
+		offsets[i] = offset
+		offset += len(line)
+		lines[i] = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+	}
+
+	emit := func(i int, isContext bool) {
+		result = append(result, LineMatch{
+			Text:       lines[i],
+			LineNumber: i + 1,
+			ByteOffset: offsets[i],
+			IsContext:  isContext,
+		})
+		lastEmitted = i
+	}
+
+	for i, line := range lines {
+		if opts.MaxCount > 0 && matched >= opts.MaxCount {
+			// Only the context after the last match is left to emit
+			if pendingAfter == 0 {
+				break
+			}
+			emit(i, true)
+			pendingAfter--
+			continue
+		}
+
+		if p.re.MatchString(line) {
+			for j := max(i-before, lastEmitted+1); j < i; j++ {
+				emit(j, true)
+			}
+			emit(i, false)
+			matched++
+			pendingAfter = after
+			continue
+		}
+
+		if pendingAfter > 0 {
+			emit(i, true)
+			pendingAfter--
+		}
+	}
+
+	return result
+}
+
+// GrepLinesAsString formats the result of GrepLines the way grep prints it:
+// matching lines are prefixed with "number:" and context lines with "number-"
+// when line numbers or byte offsets are requested, and non-adjacent groups of
+// lines are separated by the group separator when context is requested. Lines
+// are joined with the line ending style of s.
+//
+// Example:
+//
+//	p := MustCompile("error")
+//	p.GrepLinesAsString("a\nerror\nb\nc\nd\nerror", GrepOptions{Before: 1, LineNumbers: true})
+//	// Returns "1-a\n2:error\n--\n5-d\n6:error"
+func (p *Pattern) GrepLinesAsString(s string, opts GrepOptions) string {
+	matches := p.GrepLines(s, opts)
+	separator := opts.GroupSeparator
+	if separator == "" {
+		separator = DefaultGroupSeparator
+	}
+	useSeparator := !opts.NoGroupSeparator && (opts.Before > 0 || opts.After > 0)
+
+	var out []string
+	for i, m := range matches {
+		if useSeparator && i > 0 && m.LineNumber != matches[i-1].LineNumber+1 {
+			out = append(out, separator)
+		}
+
+		delimiter := ":"
+		if m.IsContext {
+			delimiter = "-"
+		}
+
+		var builder strings.Builder
+		if opts.LineNumbers {
+			builder.WriteString(strconv.Itoa(m.LineNumber))
+			builder.WriteString(delimiter)
+		}
+		if opts.ByteOffsets {
+			builder.WriteString(strconv.Itoa(m.ByteOffset))
+			builder.WriteString(delimiter)
+		}
+		builder.WriteString(m.Text)
+		out = append(out, builder.String())
+	}
+
+	// Join lines with original line ending
+	return strings.Join(out, GetLineEnding(s))
+}
+
+// GrepLines returns the lines of s matching pattern, with context lines, line
+// numbers and byte offsets as configured by opts. See Pattern.GrepLines.
+// If the pattern is invalid, an empty slice is returned.
+func GrepLines(s string, pattern string, opts GrepOptions) []LineMatch {
+	result, err := GrepLinesE(s, pattern, opts)
+	if err != nil {
+		return []LineMatch{}
+	}
+	return result
+}
+
+// GrepLinesE is like GrepLines but returns a *PatternError if pattern is invalid.
+func GrepLinesE(s string, pattern string, opts GrepOptions) ([]LineMatch, error) {
+	p, err := compilePattern(pattern)
+	if err != nil {
+		return []LineMatch{}, err
+	}
+	return p.GrepLines(s, opts), nil
+}
+
+// GrepLinesAsString returns the lines of s matching pattern formatted like grep
+// output. See Pattern.GrepLinesAsString. If the pattern is invalid, "" is returned.
+func GrepLinesAsString(s string, pattern string, opts GrepOptions) string {
+	result, err := GrepLinesAsStringE(s, pattern, opts)
+	if err != nil {
+		return ""
+	}
+	return result
+}
+
+// GrepLinesAsStringE is like GrepLinesAsString but returns a *PatternError if
+// pattern is invalid.
+func GrepLinesAsStringE(s string, pattern string, opts GrepOptions) (string, error) {
+	p, err := compilePattern(pattern)
+	if err != nil {
+		return "", err
+	}
+	return p.GrepLinesAsString(s, opts), nil
+}
@@ -0,0 +1,140 @@
+package s
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGrepLines(t *testing.T) {
+	input := "alpha\nerror one\nbeta\ngamma\ndelta\nerror two\nepsilon\n"
+
+	tests := []struct {
+		name     string
+		opts     GrepOptions
+		expected []LineMatch
+	}{
+		{
+			name: "matches only",
+			opts: GrepOptions{},
+			expected: []LineMatch{
+				{Text: "error one", LineNumber: 2, ByteOffset: 6},
+				{Text: "error two", LineNumber: 6, ByteOffset: 33},
+			},
+		},
+		{
+			name: "after context",
+			opts: GrepOptions{After: 1},
+			expected: []LineMatch{
+				{Text: "error one", LineNumber: 2, ByteOffset: 6},
+				{Text: "beta", LineNumber: 3, ByteOffset: 16, IsContext: true},
+				{Text: "error two", LineNumber: 6, ByteOffset: 33},
+				{Text: "epsilon", LineNumber: 7, ByteOffset: 43, IsContext: true},
+			},
+		},
+		{
+			name: "overlapping context is not repeated",
+			opts: GrepOptions{Before: 2, After: 2},
+			expected: []LineMatch{
+				{Text: "alpha", LineNumber: 1, ByteOffset: 0, IsContext: true},
+				{Text: "error one", LineNumber: 2, ByteOffset: 6},
+				{Text: "beta", LineNumber: 3, ByteOffset: 16, IsContext: true},
+				{Text: "gamma", LineNumber: 4, ByteOffset: 21, IsContext: true},
+				{Text: "delta", LineNumber: 5, ByteOffset: 27, IsContext: true},
+				{Text: "error two", LineNumber: 6, ByteOffset: 33},
+				{Text: "epsilon", LineNumber: 7, ByteOffset: 43, IsContext: true},
+			},
+		},
+		{
+			name: "before and after",
+			opts: GrepOptions{Before: 1, After: 1},
+			expected: []LineMatch{
+				{Text: "alpha", LineNumber: 1, ByteOffset: 0, IsContext: true},
+				{Text: "error one", LineNumber: 2, ByteOffset: 6},
+				{Text: "beta", LineNumber: 3, ByteOffset: 16, IsContext: true},
+				{Text: "delta", LineNumber: 5, ByteOffset: 27, IsContext: true},
+				{Text: "error two", LineNumber: 6, ByteOffset: 33},
+				{Text: "epsilon", LineNumber: 7, ByteOffset: 43, IsContext: true},
+			},
+		},
+		{
+			name: "before without after, like grep -C1 -A0",
+			opts: GrepOptions{Before: 1, After: 0},
+			expected: []LineMatch{
+				{Text: "alpha", LineNumber: 1, ByteOffset: 0, IsContext: true},
+				{Text: "error one", LineNumber: 2, ByteOffset: 6},
+				{Text: "delta", LineNumber: 5, ByteOffset: 27, IsContext: true},
+				{Text: "error two", LineNumber: 6, ByteOffset: 33},
+			},
+		},
+		{
+			name: "max count keeps trailing context",
+			opts: GrepOptions{MaxCount: 1, After: 2},
+			expected: []LineMatch{
+				{Text: "error one", LineNumber: 2, ByteOffset: 6},
+				{Text: "beta", LineNumber: 3, ByteOffset: 16, IsContext: true},
+				{Text: "gamma", LineNumber: 4, ByteOffset: 21, IsContext: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GrepLines(input, `error`, tt.opts)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("GrepLines(%+v) =\n%+v\nwant\n%+v", tt.opts, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGrepLinesCRLF(t *testing.T) {
+	input := "a\r\n\r\nb\r\n"
+	expected := []LineMatch{
+		{Text: "", LineNumber: 2, ByteOffset: 3},
+	}
+	if result := GrepLines(input, `^$`, GrepOptions{}); !reflect.DeepEqual(result, expected) {
+		t.Errorf("GrepLines(%q, `^$`) = %+v; want %+v", input, result, expected)
+	}
+
+	if result := GrepLines("", `.*`, GrepOptions{}); len(result) != 0 {
+		t.Errorf("GrepLines of empty string = %+v; want none", result)
+	}
+}
+
+func TestGrepLinesAsString(t *testing.T) {
+	tests := []struct {
+		input    string
+		opts     GrepOptions
+		expected string
+	}{
+		{"a\nerror\nb\nc\nd\nerror", GrepOptions{Before: 1, LineNumbers: true}, "1-a\n2:error\n--\n5-d\n6:error"},
+		{"a\r\nerror\r\nb\r\nc\r\nd\r\nerror\r\n", GrepOptions{Before: 1, LineNumbers: true}, "1-a\r\n2:error\r\n--\r\n5-d\r\n6:error"},
+		{"a\nerror\nb\nc\nd\nerror", GrepOptions{Before: 1, GroupSeparator: "==="}, "a\nerror\n===\nd\nerror"},
+		{"a\nerror\nb\nc\nd\nerror", GrepOptions{Before: 1, NoGroupSeparator: true}, "a\nerror\nd\nerror"},
+		{"a\nerror\nb\nc\nd\nerror", GrepOptions{}, "error\nerror"},
+		{"a\nerror\nb", GrepOptions{After: 1, LineNumbers: true, ByteOffsets: true}, "2:2:error\n3-8-b"},
+		{"a\nerror\nb", GrepOptions{ByteOffsets: true}, "2:error"},
+		{"nothing here", GrepOptions{Before: 5, After: 5}, ""},
+	}
+
+	for _, test := range tests {
+		result := GrepLinesAsString(test.input, `error`, test.opts)
+		if result != test.expected {
+			t.Errorf("GrepLinesAsString(%q, %+v) = %q; want %q",
+				test.input, test.opts, result, test.expected)
+		}
+	}
+
+	if result := GrepLinesAsString("a", `(`, GrepOptions{}); result != "" {
+		t.Errorf("GrepLinesAsString with invalid pattern = %q; want \"\"", result)
+	}
+	if _, err := GrepLinesE("a", `(`, GrepOptions{}); err == nil {
+		t.Error("GrepLinesE with invalid pattern returned no error")
+	}
+	if result, err := GrepLinesAsStringE("a", `(`, GrepOptions{}); result != "" || err == nil {
+		t.Errorf("GrepLinesAsStringE with invalid pattern = %q, %v; want \"\" and an error", result, err)
+	}
+	if result, err := GrepLinesAsStringE("a\nerror", `error`, GrepOptions{LineNumbers: true}); result != "2:error" || err != nil {
+		t.Errorf("GrepLinesAsStringE = %q, %v; want %q, nil", result, err, "2:error")
+	}
+}
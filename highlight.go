@@ -0,0 +1,521 @@
+package s
+
+import (
+	"errors"
+	"html/template"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// HighlightRule is a pattern to highlight with HighlightMany, along with the
+// markers to surround its matches with.
+type HighlightRule struct {
+	Pattern string
+	Left    string
+	Right   string
+	// Priority orders the rules when matches overlap: rules with a higher
+	// priority win, and rules with the same priority win in slice order.
+	Priority int
+}
+
+// OverlapPolicy decides what HighlightMany does with matches of different rules
+// that overlap.
+type OverlapPolicy int
+
+const (
+	// OverlapFirstWins keeps the match of the rule that comes first in priority
+	// order and drops the matches it overlaps.
+	OverlapFirstWins OverlapPolicy = iota
+	// OverlapLongestWins keeps the longest of overlapping matches, falling back
+	// to priority order for matches of the same length.
+	OverlapLongestWins
+	// OverlapNest keeps a match that lies entirely within another one and nests
+	// its markers inside the other's. Matches that only partly overlap are
+	// resolved as with OverlapFirstWins.
+	OverlapNest
+)
+
+// HighlightOptions configures HighlightWithOptions and HighlightManyWithOptions.
+type HighlightOptions struct {
+	// Overlap selects how overlapping matches are resolved.
+	Overlap OverlapPolicy
+	// Literal treats patterns as plain text to search for rather than regular
+	// expressions, so "a.b(c)" only matches itself.
+	Literal bool
+	// IgnoreCase matches regardless of case using Unicode simple case folding,
+	// so "σ" matches "Σ" and "ς", and "k" matches the Kelvin sign.
+	IgnoreCase bool
+	// WholeWord skips matches that are part of a longer word, that is matches
+	// that start or end with a word character next to another word character.
+	// Letters, digits, marks and underscores of any script are word characters.
+	WholeWord bool
+}
+
+// compile compiles pattern as a regular expression or a literal, as selected by opts.
+func (opts HighlightOptions) compile(pattern string) (*Pattern, error) {
+	expr := pattern
+	if opts.Literal {
+		expr = regexp.QuoteMeta(expr)
+	}
+	if opts.IgnoreCase {
+		expr = "(?i)" + expr
+	}
+
+	p, err := compilePattern(expr)
+	if err != nil {
+		// Report the pattern as given rather than the rewritten one
+		return nil, &PatternError{Pattern: pattern, Err: errors.Unwrap(err)}
+	}
+	return p, nil
+}
+
+// nonWordClass matches a character that isWordRune rejects.
+const nonWordClass = `[^\pL\p{Nd}\pM_]`
+
+// matches returns the locations of the non-empty matches of p in s that opts allow.
+func (opts HighlightOptions) matches(p *Pattern, s string) ([][]int, error) {
+	matches := p.re.FindAllStringIndex(s, -1)
+	kept := matches[:0]
+	for _, match := range matches {
+		if match[0] < match[1] && (!opts.WholeWord || !splitsWord(s, match[0]) && !splitsWord(s, match[1])) {
+			kept = append(kept, match)
+		}
+	}
+	if !opts.WholeWord {
+		return kept, nil
+	}
+
+	// Filtering loses whole words hidden behind a rejected match, as "foobar" is
+	// by "foo" for "foo|foobar", so also search with the word boundaries built
+	// into the pattern.
+	bounded, err := compilePattern(`(?:\A|` + nonWordClass + `)(` + p.re.String() + `)(?:\z|` + nonWordClass + `)`)
+	if err != nil {
+		return nil, err
+	}
+	for offset := 0; offset <= len(s); {
+		loc := bounded.re.FindStringSubmatchIndex(s[offset:])
+		if loc == nil {
+			break
+		}
+		start, end := offset+loc[2], offset+loc[3]
+		if start < end {
+			kept = append(kept, []int{start, end})
+		}
+
+		// Resume at the end of the word, so that the boundary after it can
+		// precede the next one
+		if end > offset {
+			offset = end
+		} else if offset < len(s) {
+			_, size := utf8.DecodeRuneInString(s[offset:])
+			offset += size
+		} else {
+			break
+		}
+	}
+
+	// Keep the leftmost of overlapping matches, preferring the pattern's own
+	slices.SortStableFunc(kept, func(a, b []int) int {
+		return a[0] - b[0]
+	})
+	merged := kept[:0]
+	for _, match := range kept {
+		if n := len(merged); n > 0 && match[0] < merged[n-1][1] {
+			continue
+		}
+		merged = append(merged, match)
+	}
+	return merged, nil
+}
+
+// isWordRune reports whether r is part of a word for whole word matching.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || r == '_'
+}
+
+// splitsWord reports whether byte offset i of s lies between two word characters.
+func splitsWord(s string, i int) bool {
+	if i <= 0 || i >= len(s) {
+		return false
+	}
+	before, _ := utf8.DecodeLastRuneInString(s[:i])
+	after, _ := utf8.DecodeRuneInString(s[i:])
+	return isWordRune(before) && isWordRune(after)
+}
+
+// highlightSpan is a range of bytes of the highlighted string along with the
+// markers to surround it with. rank is the position of its rule in priority order.
+type highlightSpan struct {
+	start, end  int
+	left, right string
+	rank        int
+}
+
+// contains reports whether sp covers all of other.
+func (sp highlightSpan) contains(other highlightSpan) bool {
+	return sp.start <= other.start && other.end <= sp.end
+}
+
+// overlaps reports whether sp and other share at least one byte.
+func (sp highlightSpan) overlaps(other highlightSpan) bool {
+	return sp.start < other.end && other.start < sp.end
+}
+
+// HighlightMany surrounds the matches of every rule in s with that rule's
+// markers. Where matches of different rules overlap, the rule with the highest
+// priority wins; see HighlightManyWithOptions for other policies. Empty matches
+// are ignored. If a pattern is invalid, s is returned unchanged.
+//
+// Example:
+//
+//	HighlightMany("GET /users/42 failed", []HighlightRule{
+//		{Pattern: `failed`, Left: "<red>", Right: "</red>"},
+//		{Pattern: `\d+`, Left: "<yellow>", Right: "</yellow>"},
+//	}) // Returns "GET /users/<yellow>42</yellow> <red>failed</red>"
+func HighlightMany(s string, rules []HighlightRule) string {
+	return HighlightManyWithOptions(s, rules, HighlightOptions{})
+}
+
+// HighlightManyWithOptions is like HighlightMany but accepts options selecting
+// how overlapping matches are resolved and how patterns match. If a pattern is
+// invalid, s is returned unchanged.
+func HighlightManyWithOptions(s string, rules []HighlightRule, opts HighlightOptions) string {
+	result, err := HighlightManyE(s, rules, opts)
+	if err != nil {
+		return s
+	}
+	return result
+}
+
+// HighlightWithOptions is like Highlight but accepts options for literal,
+// case-insensitive and whole word matching. Unlike Highlight, empty matches are
+// ignored. If the pattern is invalid, s is returned unchanged.
+//
+// Example:
+//
+//	opts := HighlightOptions{Literal: true, IgnoreCase: true}
+//	HighlightWithOptions("Call A.B(c) or a.b(C)", "a.b(c)", "[", "]", opts) // Returns "Call [A.B(c)] or [a.b(C)]"
+func HighlightWithOptions(s string, pattern string, left string, right string, opts HighlightOptions) string {
+	return HighlightManyWithOptions(s, []HighlightRule{{Pattern: pattern, Left: left, Right: right}}, opts)
+}
+
+// HighlightManyE is like HighlightManyWithOptions but returns a *PatternError
+// for the first pattern that does not compile.
+func HighlightManyE(s string, rules []HighlightRule, opts HighlightOptions) (string, error) {
+	spans, err := highlightSpans(s, rules, opts)
+	if err != nil {
+		return s, err
+	}
+	return renderHighlights(s, spans, nil), nil
+}
+
+// HighlightHTML is like Highlight but produces HTML: s, including the matched
+// text, is HTML-escaped while the left and right markers are inserted as is, so
+// they must be trusted HTML such as "<mark>" and "</mark>". Markers are placed
+// between escaped characters and never inside an entity. Empty matches are
+// ignored. If the pattern is invalid, the escaped s is returned.
+//
+// Example:
+//
+//	HighlightHTML("<b>Tom & Jerry</b>", "Tom|Jerry", "<mark>", "</mark>")
+//	// Returns "&lt;b&gt;<mark>Tom</mark> &amp; <mark>Jerry</mark>&lt;/b&gt;"
+func HighlightHTML(s string, pattern string, left string, right string) template.HTML {
+	return HighlightManyHTML(s, []HighlightRule{{Pattern: pattern, Left: left, Right: right}}, HighlightOptions{})
+}
+
+// HighlightManyHTML is like HighlightManyWithOptions but produces HTML, escaping s
+// and inserting the markers of the rules as is, like HighlightHTML. If a pattern
+// is invalid, the escaped s is returned.
+func HighlightManyHTML(s string, rules []HighlightRule, opts HighlightOptions) template.HTML {
+	spans, err := highlightSpans(s, rules, opts)
+	if err != nil {
+		spans = nil
+	}
+	return template.HTML(renderHighlights(s, spans, template.HTMLEscapeString))
+}
+
+// highlightSpans returns the spans of s to highlight for rules, with overlaps
+// resolved according to opts.
+func highlightSpans(s string, rules []HighlightRule, opts HighlightOptions) ([]highlightSpan, error) {
+	// Order the rules by priority, keeping slice order for equal priorities
+	ordered := slices.Clone(rules)
+	slices.SortStableFunc(ordered, func(a, b HighlightRule) int {
+		return b.Priority - a.Priority
+	})
+
+	var candidates []highlightSpan
+	for rank, rule := range ordered {
+		p, err := opts.compile(rule.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		matches, err := opts.matches(p, s)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			candidates = append(candidates, highlightSpan{
+				start: match[0],
+				end:   match[1],
+				left:  rule.Left,
+				right: rule.Right,
+				rank:  rank,
+			})
+		}
+	}
+
+	return resolveOverlaps(candidates, len(s), opts.Overlap), nil
+}
+
+// resolveOverlaps picks the spans to highlight out of candidates, which lie in a
+// string of size bytes, according to policy. The result never contains spans
+// that partly overlap, and only contains nested spans under OverlapNest.
+//
+// The matches of one rule never overlap each other, so each byte is examined at
+// most once per rule and the cost grows linearly with the size of the string.
+func resolveOverlaps(candidates []highlightSpan, size int, policy OverlapPolicy) []highlightSpan {
+	if len(candidates) == 0 {
+		return nil
+	}
+	// Matches of a single rule need no resolving
+	rank := candidates[0].rank
+	if !slices.ContainsFunc(candidates, func(sp highlightSpan) bool { return sp.rank != rank }) {
+		return candidates
+	}
+
+	// Decide which candidates are considered first
+	slices.SortStableFunc(candidates, func(a, b highlightSpan) int {
+		if policy == OverlapLongestWins {
+			if n := (b.end - b.start) - (a.end - a.start); n != 0 {
+				return n
+			}
+		}
+		if a.rank != b.rank {
+			return a.rank - b.rank
+		}
+		return a.start - b.start
+	})
+
+	if policy == OverlapNest {
+		return keepNested(candidates, size)
+	}
+
+	// covered marks the bytes of the spans kept so far
+	covered := make([]bool, size)
+	var kept []highlightSpan
+	for _, candidate := range candidates {
+		if slices.Contains(covered[candidate.start:candidate.end], true) {
+			continue
+		}
+		for i := candidate.start; i < candidate.end; i++ {
+			covered[i] = true
+		}
+		kept = append(kept, candidate)
+	}
+	return kept
+}
+
+// keepNested keeps, in order, the candidates that lie within or around each of the
+// spans kept before them, dropping those that partly overlap one.
+func keepNested(candidates []highlightSpan, size int) []highlightSpan {
+	// A candidate partly overlaps a kept span if one starts inside the candidate
+	// and ends after it, or ends inside it and starts before it. endAt[i] is the
+	// furthest end of the kept spans starting at byte i, and startAt[i] the
+	// earliest start of those ending there; both are i if there are none.
+	endAt := make([]int, size+1)
+	startAt := make([]int, size+1)
+	for i := range endAt {
+		endAt[i] = i
+		startAt[i] = i
+	}
+
+	var kept []highlightSpan
+	for _, candidate := range candidates {
+		crosses := false
+		for i := candidate.start + 1; i < candidate.end; i++ {
+			if endAt[i] > candidate.end || startAt[i] < candidate.start {
+				crosses = true
+				break
+			}
+		}
+		if crosses {
+			continue
+		}
+		endAt[candidate.start] = max(endAt[candidate.start], candidate.end)
+		startAt[candidate.end] = min(startAt[candidate.end], candidate.start)
+		kept = append(kept, candidate)
+	}
+	return kept
+}
+
+// renderHighlights writes s with the markers of spans inserted. Spans must not
+// partly overlap; nested spans are rendered with the outer markers outside the
+// inner ones, and identical spans with the higher ranked markers outside. If
+// escape is not nil, it is applied to the text between markers but not to the
+// markers themselves.
+func renderHighlights(s string, spans []highlightSpan, escape func(string) string) string {
+	if escape == nil {
+		escape = func(text string) string { return text }
+	}
+	if len(spans) == 0 {
+		return escape(s)
+	}
+
+	slices.SortFunc(spans, func(a, b highlightSpan) int {
+		if a.start != b.start {
+			return a.start - b.start
+		}
+		if a.end != b.end {
+			return b.end - a.end
+		}
+		return a.rank - b.rank
+	})
+
+	var builder strings.Builder
+	totalSize := len(s)
+	for _, sp := range spans {
+		totalSize += len(sp.left) + len(sp.right)
+	}
+	builder.Grow(totalSize)
+
+	pos := 0
+	var open []highlightSpan
+	closeUntil := func(limit int) {
+		for len(open) > 0 && open[len(open)-1].end <= limit {
+			top := open[len(open)-1]
+			builder.WriteString(escape(s[pos:top.end]))
+			builder.WriteString(top.right)
+			pos = top.end
+			open = open[:len(open)-1]
+		}
+	}
+
+	for _, sp := range spans {
+		closeUntil(sp.start)
+		builder.WriteString(escape(s[pos:sp.start]))
+		builder.WriteString(sp.left)
+		pos = sp.start
+		open = append(open, sp)
+	}
+	closeUntil(len(s))
+	builder.WriteString(escape(s[pos:]))
+
+	return builder.String()
+}
+
+// GroupHighlight selects a capture group, by index or name, for HighlightGroups
+// along with the markers to surround it with.
+type GroupHighlight struct {
+	Group string
+	Left  string
+	Right string
+}
+
+// HighlightGroup surrounds the given group (by index or name) of every match of
+// the pattern in s with the left and right markers, leaving the rest of the
+// match as is. Group "0" highlights whole matches. If the group does not exist
+// or there is no match, s is returned unchanged.
+//
+// Example:
+//
+//	p := MustCompile(`user=(\w+)`)
+//	p.HighlightGroup("user=alice", "1", "[", "]") // Returns "user=[alice]"
+func (p *Pattern) HighlightGroup(s string, group string, left string, right string) string {
+	return p.HighlightGroups(s, []GroupHighlight{{Group: group, Left: left, Right: right}})
+}
+
+// HighlightGroupE is like HighlightGroup but returns an error wrapping
+// ErrUnknownGroup if the group does not exist.
+func (p *Pattern) HighlightGroupE(s string, group string, left string, right string) (string, error) {
+	return p.HighlightGroupsE(s, []GroupHighlight{{Group: group, Left: left, Right: right}})
+}
+
+// HighlightGroups is like HighlightGroup for several groups at once, each with its
+// own markers. Nested groups get nested markers, with the outer group's markers
+// outside. Groups that do not take part in a match or match the empty string are
+// not highlighted. If a group does not exist, s is returned unchanged.
+func (p *Pattern) HighlightGroups(s string, groups []GroupHighlight) string {
+	result, err := p.HighlightGroupsE(s, groups)
+	if err != nil {
+		return s
+	}
+	return result
+}
+
+// HighlightGroupsE is like HighlightGroups but returns an error wrapping
+// ErrUnknownGroup for the first group that does not exist.
+func (p *Pattern) HighlightGroupsE(s string, groups []GroupHighlight) (string, error) {
+	indexes := make([]int, len(groups))
+	for i, g := range groups {
+		groupIdx, err := p.groupIndex(g.Group)
+		if err != nil {
+			return s, err
+		}
+		indexes[i] = groupIdx
+	}
+
+	var spans []highlightSpan
+	for _, match := range p.re.FindAllStringSubmatchIndex(s, -1) {
+		for rank, groupIdx := range indexes {
+			start, end := match[2*groupIdx], match[2*groupIdx+1]
+			if start < 0 || start == end {
+				continue
+			}
+			spans = append(spans, highlightSpan{
+				start: start,
+				end:   end,
+				left:  groups[rank].Left,
+				right: groups[rank].Right,
+				rank:  rank,
+			})
+		}
+	}
+
+	return renderHighlights(s, spans, nil), nil
+}
+
+// HighlightGroup surrounds the given group (by index or name) of every match of
+// pattern in s with the left and right markers. If the pattern is invalid or the
+// group does not exist, s is returned unchanged.
+func HighlightGroup(s string, pattern string, group string, left string, right string) string {
+	result, err := HighlightGroupE(s, pattern, group, left, right)
+	if err != nil {
+		return s
+	}
+	return result
+}
+
+// HighlightGroupE is like HighlightGroup but returns a *PatternError if the pattern
+// does not compile and an error wrapping ErrUnknownGroup if the group does not exist.
+func HighlightGroupE(s string, pattern string, group string, left string, right string) (string, error) {
+	p, err := compilePattern(pattern)
+	if err != nil {
+		return s, err
+	}
+	return p.HighlightGroupE(s, group, left, right)
+}
+
+// HighlightGroups surrounds several groups of every match of pattern in s, each
+// with its own markers. See Pattern.HighlightGroups. If the pattern is invalid or
+// a group does not exist, s is returned unchanged.
+func HighlightGroups(s string, pattern string, groups []GroupHighlight) string {
+	result, err := HighlightGroupsE(s, pattern, groups)
+	if err != nil {
+		return s
+	}
+	return result
+}
+
+// HighlightGroupsE is like HighlightGroups but returns a *PatternError if the
+// pattern does not compile and an error wrapping ErrUnknownGroup if a group does
+// not exist.
+func HighlightGroupsE(s string, pattern string, groups []GroupHighlight) (string, error) {
+	p, err := compilePattern(pattern)
+	if err != nil {
+		return s, err
+	}
+	return p.HighlightGroupsE(s, groups)
+}
@@ -0,0 +1,395 @@
+package s
+
+import (
+	"cmp"
+	"errors"
+	"html/template"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestHighlightMany(t *testing.T) {
+	logRules := []HighlightRule{
+		{Pattern: `ERROR`, Left: "<r>", Right: "</r>"},
+		{Pattern: `id=\d+`, Left: "<y>", Right: "</y>"},
+		{Pattern: `https?://\S+`, Left: "<b>", Right: "</b>"},
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		rules    []HighlightRule
+		opts     HighlightOptions
+		expected string
+	}{
+		{
+			name:     "distinct markers per rule",
+			input:    "ERROR id=42 fetching https://example.com/x",
+			rules:    logRules,
+			expected: "<r>ERROR</r> <y>id=42</y> fetching <b>https://example.com/x</b>",
+		},
+		{
+			name:     "no matches",
+			input:    "all good",
+			rules:    logRules,
+			expected: "all good",
+		},
+		{
+			name:  "first rule wins",
+			input: "https://example.com/?id=7",
+			rules: logRules,
+			// The URL rule comes after the id rule, so the id wins
+			expected: "https://example.com/?<y>id=7</y>",
+		},
+		{
+			name:  "priority overrides order",
+			input: "https://example.com/?id=7",
+			rules: []HighlightRule{
+				logRules[1],
+				{Pattern: `https?://\S+`, Left: "<b>", Right: "</b>", Priority: 1},
+			},
+			expected: "<b>https://example.com/?id=7</b>",
+		},
+		{
+			name:     "longest wins",
+			input:    "https://example.com/?id=7",
+			rules:    logRules,
+			opts:     HighlightOptions{Overlap: OverlapLongestWins},
+			expected: "<b>https://example.com/?id=7</b>",
+		},
+		{
+			name:  "longest wins ties go to priority",
+			input: "abc",
+			rules: []HighlightRule{
+				{Pattern: `ab`, Left: "[", Right: "]"},
+				{Pattern: `bc`, Left: "{", Right: "}"},
+			},
+			opts:     HighlightOptions{Overlap: OverlapLongestWins},
+			expected: "[ab]c",
+		},
+		{
+			name:     "nesting",
+			input:    "https://example.com/?id=7 and id=8",
+			rules:    logRules,
+			opts:     HighlightOptions{Overlap: OverlapNest},
+			expected: "<b>https://example.com/?<y>id=7</y></b> and <y>id=8</y>",
+		},
+		{
+			name:  "nesting of identical matches puts priority outside",
+			input: "x 42 y",
+			rules: []HighlightRule{
+				{Pattern: `\d+`, Left: "[", Right: "]"},
+				{Pattern: `42`, Left: "{", Right: "}", Priority: 1},
+			},
+			opts:     HighlightOptions{Overlap: OverlapNest},
+			expected: "x {[42]} y",
+		},
+		{
+			name:  "nesting resolves partial overlaps by priority",
+			input: "abcd",
+			rules: []HighlightRule{
+				{Pattern: `bcd`, Left: "{", Right: "}"},
+				{Pattern: `abc`, Left: "[", Right: "]"},
+				{Pattern: `b`, Left: "<", Right: ">"},
+			},
+			opts:     HighlightOptions{Overlap: OverlapNest},
+			expected: "a{<b>cd}",
+		},
+		{
+			name:  "empty matches are ignored",
+			input: "abc",
+			rules: []HighlightRule{
+				{Pattern: `x*`, Left: "[", Right: "]"},
+				{Pattern: `b`, Left: "<", Right: ">"},
+			},
+			expected: "a<b>c",
+		},
+		{
+			name:     "unicode",
+			input:    "你好世界",
+			rules:    []HighlightRule{{Pattern: `世界`, Left: "*", Right: "*"}},
+			expected: "你好*世界*",
+		},
+		{
+			name:     "no rules",
+			input:    "abc",
+			rules:    nil,
+			expected: "abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := HighlightManyWithOptions(tt.input, tt.rules, tt.opts)
+			if result != tt.expected {
+				t.Errorf("HighlightManyWithOptions(%q) = %q; want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHighlightManyMatchesHighlight(t *testing.T) {
+	inputs := []string{"hello world", "你好世界", "a1b22c333", ""}
+	for _, input := range inputs {
+		want := Highlight(input, `\w\d+|o`, "[", "]")
+		got := HighlightMany(input, []HighlightRule{{Pattern: `\w\d+|o`, Left: "[", Right: "]"}})
+		if got != want {
+			t.Errorf("HighlightMany(%q) = %q; Highlight returned %q", input, got, want)
+		}
+	}
+}
+
+func TestHighlightManyInvalidPattern(t *testing.T) {
+	rules := []HighlightRule{
+		{Pattern: `a`, Left: "[", Right: "]"},
+		{Pattern: `(`, Left: "[", Right: "]"},
+	}
+
+	if result := HighlightMany("abc", rules); result != "abc" {
+		t.Errorf("HighlightMany with invalid pattern = %q; want %q", result, "abc")
+	}
+
+	_, err := HighlightManyE("abc", rules, HighlightOptions{})
+	var patternErr *PatternError
+	if !errors.As(err, &patternErr) || patternErr.Pattern != `(` {
+		t.Errorf("HighlightManyE error = %v; want *PatternError for %q", err, `(`)
+	}
+}
+
+func TestHighlightGroup(t *testing.T) {
+	tests := []struct {
+		input    string
+		pattern  string
+		group    string
+		expected string
+	}{
+		{"user=alice id=1 user=bob", `user=(\w+)`, "1", "user=[alice] id=1 user=[bob]"},
+		{"user=alice id=1", `id=(?P<id>\d+)`, "id", "user=alice id=[1]"},
+		{"user=alice", `user=(\w+)`, "0", "[user=alice]"},
+		{"a=1 b=", `(\w)=(\d*)`, "2", "a=[1] b="},
+		{"ab b", `(a)?b`, "1", "[a]b b"},
+		{"user=alice", `user=(\w+)`, "2", "user=alice"},
+		{"user=alice", `user=(\w+)`, "name", "user=alice"},
+		{"user=alice", `user=(`, "1", "user=alice"},
+		{"你好=世界", `(\p{Han}+)=(\p{Han}+)`, "2", "你好=[世界]"},
+	}
+
+	for _, test := range tests {
+		result := HighlightGroup(test.input, test.pattern, test.group, "[", "]")
+		if result != test.expected {
+			t.Errorf("HighlightGroup(%q, %q, %q) = %q; want %q",
+				test.input, test.pattern, test.group, result, test.expected)
+		}
+	}
+}
+
+func TestHighlightGroups(t *testing.T) {
+	groups := []GroupHighlight{
+		{Group: "key", Left: "<k>", Right: "</k>"},
+		{Group: "value", Left: "<v>", Right: "</v>"},
+	}
+	input := "host=example.org port=8080"
+	expected := "<k>host</k>=<v>example.org</v> <k>port</k>=<v>8080</v>"
+	if result := HighlightGroups(input, `(?P<key>\w+)=(?P<value>\S+)`, groups); result != expected {
+		t.Errorf("HighlightGroups(%q) = %q; want %q", input, result, expected)
+	}
+
+	// Nested groups nest their markers
+	nested := []GroupHighlight{
+		{Group: "2", Left: "{", Right: "}"},
+		{Group: "1", Left: "[", Right: "]"},
+	}
+	if result := HighlightGroups("v1.22", `v((\d+)\.\d+)`, nested); result != "v[{1}.22]" {
+		t.Errorf("HighlightGroups with nested groups = %q; want %q", result, "v[{1}.22]")
+	}
+
+	// The same group twice puts the first markers outside
+	twice := []GroupHighlight{
+		{Group: "1", Left: "(", Right: ")"},
+		{Group: "1", Left: "[", Right: "]"},
+	}
+	if result := HighlightGroups("x=1", `x=(\d)`, twice); result != "x=([1])" {
+		t.Errorf("HighlightGroups with a repeated group = %q; want %q", result, "x=([1])")
+	}
+}
+
+func TestHighlightGroupErrors(t *testing.T) {
+	_, err := HighlightGroupE("user=alice", `user=(\w+)`, "missing", "[", "]")
+	if !errors.Is(err, ErrUnknownGroup) {
+		t.Errorf("HighlightGroupE with unknown group error = %v; want ErrUnknownGroup", err)
+	}
+
+	_, err = HighlightGroupsE("user=alice", `user=(\w+)`, []GroupHighlight{{Group: "1"}, {Group: "9"}})
+	if !errors.Is(err, ErrUnknownGroup) {
+		t.Errorf("HighlightGroupsE with unknown group error = %v; want ErrUnknownGroup", err)
+	}
+
+	_, err = HighlightGroupE("user=alice", `user=(`, "1", "[", "]")
+	var patternErr *PatternError
+	if !errors.As(err, &patternErr) {
+		t.Errorf("HighlightGroupE with invalid pattern error = %v; want *PatternError", err)
+	}
+}
+
+func TestHighlightWithOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		pattern  string
+		opts     HighlightOptions
+		expected string
+	}{
+		{"regex by default", "a.b(c) axbc", "a.b(c)", HighlightOptions{}, "a.b(c) [axbc]"},
+		{"literal", "a.b(c) axbc", "a.b(c)", HighlightOptions{Literal: true}, "[a.b(c)] axbc"},
+		{"literal invalid regex", "f(x) = [1", "[1", HighlightOptions{Literal: true}, "f(x) = [[1]"},
+		{"literal empty", "abc", "", HighlightOptions{Literal: true}, "abc"},
+		{"ignore case", "Go GO go", "go", HighlightOptions{IgnoreCase: true}, "[Go] [GO] [go]"},
+		{"ignore case alternation", "Cat DOG", "cat|dog", HighlightOptions{IgnoreCase: true}, "[Cat] [DOG]"},
+		{"ignore case greek", "ΟΔΥΣΣΕΥΣ οδυσσευς", "οδυσσευσ", HighlightOptions{IgnoreCase: true, Literal: true}, "[ΟΔΥΣΣΕΥΣ] [οδυσσευς]"},
+		{"ignore case kelvin sign", "300K", "300k", HighlightOptions{IgnoreCase: true}, "[300K]"},
+		{"ignore case literal", "A.B(c) a.b(C)", "a.b(c)", HighlightOptions{Literal: true, IgnoreCase: true}, "[A.B(c)] [a.b(C)]"},
+		{"whole word", "cat catalog bobcat cat_1 cat.", "cat", HighlightOptions{WholeWord: true}, "[cat] catalog bobcat cat_1 [cat]."},
+		{"whole word unicode", "über überall Hinüber über", "über", HighlightOptions{WholeWord: true}, "[über] überall Hinüber [über]"},
+		{"whole word combining mark", "cafe cafe\u0301", "cafe", HighlightOptions{WholeWord: true}, "[cafe] cafe\u0301"},
+		{"whole word punctuation edges", "call f(x) or f(x)y", "f(x)", HighlightOptions{WholeWord: true, Literal: true}, "call [f(x)] or [f(x)]y"},
+		{"whole word cjk", "東京都 東京", "東京", HighlightOptions{WholeWord: true}, "東京都 [東京]"},
+		{"whole word behind rejected match", "foobar foo", "foo|foobar", HighlightOptions{WholeWord: true}, "[foobar] [foo]"},
+		{"whole word adjacent", "foo foo,foo", "foo|foobar", HighlightOptions{WholeWord: true}, "[foo] [foo],[foo]"},
+		{"whole word ignore case", "FooBar foo", "foo|foobar", HighlightOptions{WholeWord: true, IgnoreCase: true}, "[FooBar] [foo]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := HighlightWithOptions(tt.input, tt.pattern, "[", "]", tt.opts)
+			if result != tt.expected {
+				t.Errorf("HighlightWithOptions(%q, %q, %+v) = %q; want %q",
+					tt.input, tt.pattern, tt.opts, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHighlightOptionsErrors(t *testing.T) {
+	if result := HighlightWithOptions("a(b", "(", "[", "]", HighlightOptions{IgnoreCase: true}); result != "a(b" {
+		t.Errorf("HighlightWithOptions with invalid pattern = %q; want %q", result, "a(b")
+	}
+
+	_, err := HighlightManyE("a(b", []HighlightRule{{Pattern: "("}}, HighlightOptions{IgnoreCase: true})
+	var patternErr *PatternError
+	if !errors.As(err, &patternErr) || patternErr.Pattern != "(" {
+		t.Errorf("HighlightManyE error = %v; want *PatternError for %q", err, "(")
+	}
+}
+
+func TestHighlightHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		pattern  string
+		expected template.HTML
+	}{
+		{"text is escaped", "<b>Tom & Jerry</b>", "Tom|Jerry", "&lt;b&gt;<mark>Tom</mark> &amp; <mark>Jerry</mark>&lt;/b&gt;"},
+		{"matched text is escaped", `x<script>y`, `<script>`, "x<mark>&lt;script&gt;</mark>y"},
+		{"markers stay outside entities", `a&b"c`, `.`, `<mark>a</mark><mark>&amp;</mark><mark>b</mark><mark>&#34;</mark><mark>c</mark>`},
+		{"entity-like input is text", "&amp;", "amp", "&amp;<mark>amp</mark>;"},
+		{"no match", "1 < 2", `\d{3}`, "1 &lt; 2"},
+		{"invalid pattern", "<i>", "(", "&lt;i&gt;"},
+		{"unicode", "你好<世界>", "世界", "你好&lt;<mark>世界</mark>&gt;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := HighlightHTML(tt.input, tt.pattern, "<mark>", "</mark>")
+			if result != tt.expected {
+				t.Errorf("HighlightHTML(%q, %q) = %q; want %q", tt.input, tt.pattern, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHighlightManyHTML(t *testing.T) {
+	rules := []HighlightRule{
+		{Pattern: "a.b", Left: `<span class="x">`, Right: "</span>"},
+		{Pattern: "<c>", Left: "<em>", Right: "</em>"},
+	}
+	result := HighlightManyHTML("A.B <c> axb", rules, HighlightOptions{Literal: true, IgnoreCase: true})
+	expected := template.HTML(`<span class="x">A.B</span> <em>&lt;c&gt;</em> axb`)
+	if result != expected {
+		t.Errorf("HighlightManyHTML = %q; want %q", result, expected)
+	}
+}
+
+func TestHighlightHTMLInTemplate(t *testing.T) {
+	tmpl := template.Must(template.New("result").Parse(`<p>{{.}}</p>`))
+	var out strings.Builder
+	if err := tmpl.Execute(&out, HighlightHTML(`<img src=x onerror=alert(1)> alert`, "alert", "<mark>", "</mark>")); err != nil {
+		t.Fatal(err)
+	}
+	expected := `<p>&lt;img src=x onerror=<mark>alert</mark>(1)&gt; <mark>alert</mark></p>`
+	if out.String() != expected {
+		t.Errorf("template output = %q; want %q", out.String(), expected)
+	}
+}
+
+// naiveResolveOverlaps is resolveOverlaps checking every candidate against every
+// kept span.
+func naiveResolveOverlaps(candidates []highlightSpan, policy OverlapPolicy) []highlightSpan {
+	slices.SortStableFunc(candidates, func(a, b highlightSpan) int {
+		if policy == OverlapLongestWins {
+			if n := (b.end - b.start) - (a.end - a.start); n != 0 {
+				return n
+			}
+		}
+		if a.rank != b.rank {
+			return a.rank - b.rank
+		}
+		return a.start - b.start
+	})
+
+	var kept []highlightSpan
+	for _, candidate := range candidates {
+		accepted := true
+		for _, other := range kept {
+			if candidate.overlaps(other) &&
+				!(policy == OverlapNest && (other.contains(candidate) || candidate.contains(other))) {
+				accepted = false
+				break
+			}
+		}
+		if accepted {
+			kept = append(kept, candidate)
+		}
+	}
+	return kept
+}
+
+func TestResolveOverlapsMatchesNaive(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	const size = 40
+	for round := 0; round < 500; round++ {
+		// Like the matches of a rule, the spans of each rank do not overlap
+		var candidates []highlightSpan
+		for rank := 0; rank < 1+rng.IntN(4); rank++ {
+			for pos := rng.IntN(4); pos < size; {
+				end := min(pos+1+rng.IntN(8), size)
+				candidates = append(candidates, highlightSpan{start: pos, end: end, rank: rank})
+				pos = end + rng.IntN(6)
+			}
+		}
+
+		for _, policy := range []OverlapPolicy{OverlapFirstWins, OverlapLongestWins, OverlapNest} {
+			got := resolveOverlaps(slices.Clone(candidates), size, policy)
+			want := naiveResolveOverlaps(slices.Clone(candidates), policy)
+			order := func(a, b highlightSpan) int {
+				return cmp.Or(a.start-b.start, a.end-b.end, a.rank-b.rank)
+			}
+			slices.SortFunc(got, order)
+			slices.SortFunc(want, order)
+			if !slices.Equal(got, want) {
+				t.Fatalf("resolveOverlaps(%v, %d) = %v; want %v", candidates, policy, got, want)
+			}
+		}
+	}
+}
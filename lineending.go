@@ -0,0 +1,179 @@
+package s
+
+import (
+	"strings"
+)
+
+// Line ending styles recognized by GetLineEndingInfo and NormalizeLineEndings.
+const (
+	LineEndingLF   = "\n"
+	LineEndingCRLF = "\r\n"
+	LineEndingCR   = "\r"
+)
+
+// LineEndingInfo describes the line endings found in a string.
+type LineEndingInfo struct {
+	// LF, CRLF and CR count the "\n", "\r\n" and lone "\r" line endings.
+	LF, CRLF, CR int
+	// TrailingNewline reports whether the string ends with a line ending.
+	TrailingNewline bool
+	// Dominant is the most frequent line ending. Ties prefer CRLF, then LF,
+	// then CR, and a string without line endings reports LF.
+	Dominant string
+}
+
+// Mixed reports whether more than one line ending style was found.
+func (info LineEndingInfo) Mixed() bool {
+	styles := 0
+	for _, n := range []int{info.LF, info.CRLF, info.CR} {
+		if n > 0 {
+			styles++
+		}
+	}
+	return styles > 1
+}
+
+// GetLineEndingInfo counts the LF, CRLF and lone CR line endings of s. Unlike
+// GetLineEnding, which only reports whether s contains "\r\n", it recognizes
+// classic Mac line endings and reports files with mixed endings.
+//
+// Example:
+//
+//	GetLineEndingInfo("a\r\nb\nc\r\n") // Returns {LF: 1, CRLF: 2, Dominant: "\r\n", TrailingNewline: true}
+func GetLineEndingInfo(s string) LineEndingInfo {
+	var info LineEndingInfo
+	for _, line := range splitLines(s) {
+		switch line.ending {
+		case LineEndingLF:
+			info.LF++
+		case LineEndingCRLF:
+			info.CRLF++
+		case LineEndingCR:
+			info.CR++
+		}
+	}
+
+	info.TrailingNewline = strings.HasSuffix(s, "\n") || strings.HasSuffix(s, "\r")
+
+	info.Dominant = LineEndingLF
+	switch {
+	case info.CRLF > 0 && info.CRLF >= info.LF && info.CRLF >= info.CR:
+		info.Dominant = LineEndingCRLF
+	case info.CR > info.LF:
+		info.Dominant = LineEndingCR
+	}
+	return info
+}
+
+// NormalizeLineEndings replaces every line ending of s, whether "\n", "\r\n" or
+// a lone "\r", with lineEnding. Unlike ToLinuxLineEnding and ToWindowsLineEnding,
+// lone carriage returns are treated as line endings too.
+//
+// Example:
+//
+//	NormalizeLineEndings("a\rb\r\nc\n", "\n") // Returns "a\nb\nc\n"
+func NormalizeLineEndings(s string, lineEnding string) string {
+	if !strings.ContainsAny(s, "\r\n") {
+		return s
+	}
+
+	var builder strings.Builder
+	builder.Grow(len(s))
+	for _, line := range splitLines(s) {
+		builder.WriteString(line.text)
+		if line.ending != "" {
+			builder.WriteString(lineEnding)
+		}
+	}
+	return builder.String()
+}
+
+// textLine is a line of text along with the line ending that terminated it, which
+// is empty for the last line.
+type textLine struct {
+	text   string
+	ending string
+}
+
+// splitLines splits s after every "\n", "\r\n" or lone "\r", keeping each line
+// ending. Like strings.Split, it returns one more line than there are line
+// endings, so the last line is empty when s ends with a line ending.
+func splitLines(s string) []textLine {
+	var lines []textLine
+	for {
+		i := strings.IndexAny(s, "\r\n")
+		if i < 0 {
+			return append(lines, textLine{text: s})
+		}
+		n := 1
+		if s[i] == '\r' && i+1 < len(s) && s[i+1] == '\n' {
+			n = 2
+		}
+		lines = append(lines, textLine{text: s[:i], ending: s[i : i+n]})
+		s = s[i+n:]
+	}
+}
+
+// IndentOptions configures IndentWithOptions and UnindentWithOptions.
+type IndentOptions struct {
+	// PreserveLineEndings keeps the original line ending of every line,
+	// including lone carriage returns, instead of converting them all to the
+	// style reported by GetLineEnding.
+	PreserveLineEndings bool
+}
+
+// IndentWithOptions is like Indent but accepts options controlling how line
+// endings are handled.
+//
+// Example:
+//
+//	IndentWithOptions("a\r\nb\nc", "  ", IndentOptions{PreserveLineEndings: true}) // Returns "  a\r\n  b\n  c"
+func IndentWithOptions(s string, indent string, opts IndentOptions) string {
+	if !opts.PreserveLineEndings {
+		return Indent(s, indent)
+	}
+
+	var builder strings.Builder
+	for _, line := range splitLines(s) {
+		builder.WriteString(indent)
+		builder.WriteString(line.text)
+		builder.WriteString(line.ending)
+	}
+	return builder.String()
+}
+
+// UnindentWithOptions is like Unindent but accepts options controlling how line
+// endings are handled. When preserving line endings, the ending of the last line
+// kept is dropped along with the blank line that followed it, as Unindent does.
+func UnindentWithOptions(s string, opts IndentOptions) string {
+	if !opts.PreserveLineEndings {
+		return Unindent(s)
+	}
+
+	lines := splitLines(s)
+	if len(lines) > 0 && Trim(lines[0].text) == "" {
+		lines = lines[1:]
+	}
+	if len(lines) > 0 && Trim(lines[len(lines)-1].text) == "" {
+		lines = lines[:len(lines)-1]
+		if len(lines) > 0 {
+			lines[len(lines)-1].ending = ""
+		}
+	}
+
+	texts := make([]string, len(lines))
+	for i, line := range lines {
+		texts[i] = line.text
+	}
+	indent := GetIndentStringArray(texts)
+
+	var builder strings.Builder
+	for _, line := range lines {
+		if len(line.text) >= indent {
+			line.text = line.text[indent:]
+		}
+		builder.WriteString(line.text)
+		builder.WriteString(line.ending)
+	}
+	return builder.String()
+}
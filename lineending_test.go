@@ -0,0 +1,110 @@
+package s
+
+import (
+	"testing"
+)
+
+func TestGetLineEndingInfo(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected LineEndingInfo
+		mixed    bool
+	}{
+		{"", LineEndingInfo{Dominant: "\n"}, false},
+		{"no line ending", LineEndingInfo{Dominant: "\n"}, false},
+		{"a\nb\n", LineEndingInfo{LF: 2, TrailingNewline: true, Dominant: "\n"}, false},
+		{"a\r\nb", LineEndingInfo{CRLF: 1, Dominant: "\r\n"}, false},
+		{"a\rb\rc\r", LineEndingInfo{CR: 3, TrailingNewline: true, Dominant: "\r"}, false},
+		{"a\r\nb\nc\r\n", LineEndingInfo{LF: 1, CRLF: 2, TrailingNewline: true, Dominant: "\r\n"}, true},
+		{"a\nb\r\n", LineEndingInfo{LF: 1, CRLF: 1, TrailingNewline: true, Dominant: "\r\n"}, true},
+		{"a\nb\nc\rd\r\n", LineEndingInfo{LF: 2, CRLF: 1, CR: 1, TrailingNewline: true, Dominant: "\n"}, true},
+		{"a\rb\rc\n", LineEndingInfo{LF: 1, CR: 2, TrailingNewline: true, Dominant: "\r"}, true},
+		{"\n\r", LineEndingInfo{LF: 1, CR: 1, TrailingNewline: true, Dominant: "\n"}, true},
+		{"\r\r\n", LineEndingInfo{CRLF: 1, CR: 1, TrailingNewline: true, Dominant: "\r\n"}, true},
+	}
+
+	for _, test := range tests {
+		result := GetLineEndingInfo(test.input)
+		if result != test.expected {
+			t.Errorf("GetLineEndingInfo(%q) = %+v; want %+v",
+				test.input, result, test.expected)
+		}
+		if result.Mixed() != test.mixed {
+			t.Errorf("GetLineEndingInfo(%q).Mixed() = %v; want %v",
+				test.input, result.Mixed(), test.mixed)
+		}
+	}
+}
+
+func TestNormalizeLineEndings(t *testing.T) {
+	tests := []struct {
+		input      string
+		lineEnding string
+		expected   string
+	}{
+		{"a\rb\r\nc\n", "\n", "a\nb\nc\n"},
+		{"a\rb\r\nc\n", "\r\n", "a\r\nb\r\nc\r\n"},
+		{"a\rb\r\nc\n", "\r", "a\rb\rc\r"},
+		{"\r\r", "\n", "\n\n"},
+		{"\n\r", "\r\n", "\r\n\r\n"},
+		{"\r\n\r\n", "\n", "\n\n"},
+		{"plain", "\r\n", "plain"},
+		{"", "\n", ""},
+	}
+
+	for _, test := range tests {
+		result := NormalizeLineEndings(test.input, test.lineEnding)
+		if result != test.expected {
+			t.Errorf("NormalizeLineEndings(%q, %q) = %q; want %q",
+				test.input, test.lineEnding, result, test.expected)
+		}
+	}
+}
+
+func TestIndentWithOptions(t *testing.T) {
+	tests := []struct {
+		input    string
+		indent   string
+		opts     IndentOptions
+		expected string
+	}{
+		{"a\r\nb\nc", "  ", IndentOptions{}, "  a\r\n  b\r\n  c"},
+		{"a\r\nb\nc", "  ", IndentOptions{PreserveLineEndings: true}, "  a\r\n  b\n  c"},
+		{"line1\rline2\rline3", ">>", IndentOptions{PreserveLineEndings: true}, ">>line1\r>>line2\r>>line3"},
+		{"\nline1\r\n", "  ", IndentOptions{PreserveLineEndings: true}, "  \n  line1\r\n  "},
+		{"", "  ", IndentOptions{PreserveLineEndings: true}, "  "},
+	}
+
+	for _, test := range tests {
+		result := IndentWithOptions(test.input, test.indent, test.opts)
+		if result != test.expected {
+			t.Errorf("IndentWithOptions(%q, %q, %+v) = %q; want %q",
+				test.input, test.indent, test.opts, result, test.expected)
+		}
+	}
+}
+
+func TestUnindentWithOptions(t *testing.T) {
+	preserve := IndentOptions{PreserveLineEndings: true}
+	tests := []struct {
+		input    string
+		opts     IndentOptions
+		expected string
+	}{
+		{"\n  hello\r\n  world\n  ", IndentOptions{}, "hello\r\nworld"},
+		{"\n  hello\r\n  world\n  ", preserve, "hello\r\nworld"},
+		{"\n  hello\n  world\r\n    indented\n", preserve, "hello\nworld\r\n  indented"},
+		{"\r    a\r  b\r", preserve, "  a\rb"},
+		{"   spaces   ", preserve, "spaces   "},
+		{"", preserve, ""},
+		{" \t\t", preserve, ""},
+	}
+
+	for _, test := range tests {
+		result := UnindentWithOptions(test.input, test.opts)
+		if result != test.expected {
+			t.Errorf("UnindentWithOptions(%q, %+v) = %q; want %q",
+				test.input, test.opts, result, test.expected)
+		}
+	}
+}
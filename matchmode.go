@@ -0,0 +1,126 @@
+package s
+
+import (
+	"errors"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// MatchMode selects which part of a string a pattern has to match.
+type MatchMode int
+
+const (
+	// MatchContains matches if the pattern matches anywhere in the string.
+	MatchContains MatchMode = iota
+	// MatchPrefix matches if the pattern matches at the start of the string.
+	MatchPrefix
+	// MatchSuffix matches if the pattern matches at the end of the string.
+	MatchSuffix
+	// MatchFull matches if the pattern matches the whole string.
+	MatchFull
+	// MatchCompat matches like IsMatch always has: ".*" is added to the start
+	// of the pattern unless it starts with "^" and to the end unless it ends
+	// with "$". Because this rewrites the pattern text, alternations are split
+	// ("a|b" becomes ".*a|b.*"), which is rarely what is wanted.
+	MatchCompat
+)
+
+// IsMatchWithMode reports whether the pattern matches s in the given mode. The
+// prefix, suffix and full modes anchor the parsed pattern as a whole, so
+// alternations and flag groups keep their meaning: in MatchFull mode "a|b"
+// matches "a" and "b" but not "ab", and "(?i)abc" matches "ABC".
+func (p *Pattern) IsMatchWithMode(s string, mode MatchMode) bool {
+	if mode == MatchContains {
+		return p.re.MatchString(s)
+	}
+	re := p.modeRegexp(mode)
+	return re != nil && re.MatchString(s)
+}
+
+// modeRegexp returns the pattern rewritten for mode, or nil if mode is unknown or
+// the rewritten pattern does not compile. Each rewrite is compiled once per
+// Pattern and kept out of the regex cache.
+func (p *Pattern) modeRegexp(mode MatchMode) *regexp.Regexp {
+	if mode < 0 || int(mode) >= len(p.modes) {
+		return nil
+	}
+	m := &p.modes[mode]
+	m.once.Do(func() {
+		var pattern string
+		if mode == MatchCompat {
+			pattern = compatPattern(p.re.String())
+		} else {
+			anchored, err := anchoredPattern(p.re.String(), mode)
+			if err != nil {
+				return
+			}
+			pattern = anchored
+		}
+		m.re, _ = regexp.Compile(pattern)
+	})
+	return m.re
+}
+
+// anchoredPattern returns regex anchored at the start, the end or both of the
+// text, as selected by mode. The anchors are added to the parsed syntax tree
+// rather than the pattern text.
+func anchoredPattern(regex string, mode MatchMode) (string, error) {
+	// regexp.Compile parses with the Perl flags
+	re, err := syntax.Parse(regex, syntax.Perl)
+	if err != nil {
+		return "", err
+	}
+
+	sub := []*syntax.Regexp{re}
+	if mode == MatchPrefix || mode == MatchFull {
+		sub = append([]*syntax.Regexp{{Op: syntax.OpBeginText}}, sub...)
+	}
+	if mode == MatchSuffix || mode == MatchFull {
+		sub = append(sub, &syntax.Regexp{Op: syntax.OpEndText})
+	}
+
+	return (&syntax.Regexp{Op: syntax.OpConcat, Sub: sub}).String(), nil
+}
+
+// compatPattern rewrites regex the way IsMatch always has.
+func compatPattern(regex string) string {
+	// If regex doesn't start with ^, allow partial matches from start
+	if !strings.HasPrefix(regex, "^") {
+		regex = ".*" + regex
+	}
+
+	// If regex doesn't end with $, allow partial matches at end
+	if !strings.HasSuffix(regex, "$") {
+		regex = regex + ".*"
+	}
+
+	return regex
+}
+
+// IsMatchWithMode reports whether pattern matches s in the given mode. See
+// Pattern.IsMatchWithMode. IsMatch is IsMatchWithMode in MatchCompat mode.
+// If the pattern is invalid, false is returned.
+func IsMatchWithMode(s string, pattern string, mode MatchMode) bool {
+	matched, _ := IsMatchWithModeE(s, pattern, mode)
+	return matched
+}
+
+// IsMatchWithModeE is like IsMatchWithMode but returns a *PatternError if the
+// pattern does not compile.
+func IsMatchWithModeE(s string, pattern string, mode MatchMode) (bool, error) {
+	if mode == MatchCompat {
+		// Compile the rewritten pattern so that errors match IsMatchE's
+		p, err := compilePattern(compatPattern(pattern))
+		if err != nil {
+			return false, &PatternError{Pattern: pattern, Err: errors.Unwrap(err)}
+		}
+		return p.IsMatch(s), nil
+	}
+
+	p, err := compilePattern(pattern)
+	if err != nil {
+		return false, err
+	}
+	return p.IsMatchWithMode(s, mode), nil
+}
@@ -0,0 +1,135 @@
+package s
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestIsMatchWithMode(t *testing.T) {
+	tests := []struct {
+		s        string
+		pattern  string
+		contains bool
+		prefix   bool
+		suffix   bool
+		full     bool
+		compat   bool
+	}{
+		{"hello world", "world", true, false, true, false, true},
+		{"hello world", "hello", true, true, false, false, true},
+		{"hello", "hello", true, true, true, true, true},
+		{"hello", "ell", true, false, false, false, true},
+		{"hello", "xyz", false, false, false, false, false},
+
+		// Alternation is anchored as a whole
+		{"a", "a|b", true, true, true, true, true},
+		{"b", "a|b", true, true, true, true, true},
+		{"ab", "a|b", true, true, true, false, true},
+		{"xb", "^a|b", true, false, true, false, true},
+		{"bx", "a|b$", false, false, false, false, false},
+		{"ax", "a|b$", true, true, false, false, true},
+
+		// Flag groups keep their meaning
+		{"ABC", "(?i)abc", true, true, true, true, true},
+		{"xABCx", "(?i)abc", true, false, false, false, true},
+		{"Ab", "(?i:a)b", true, true, true, true, true},
+		{"AB", "(?i:a)b", false, false, false, false, false},
+		{"a\nb", "(?s)a.b", true, true, true, true, true},
+		{"a\nb", "a.b", false, false, false, false, false},
+		{"x\nab", "(?m)^ab$", true, false, true, false, true},
+
+		// Empty patterns and strings
+		{"", "", true, true, true, true, true},
+		{"abc", "", true, true, true, false, true},
+		{"", "a*", true, true, true, true, true},
+		{"你好世界", "你好", true, true, false, false, true},
+		{"你好世界", "\\p{Han}+", true, true, true, true, true},
+	}
+
+	for _, tt := range tests {
+		expected := map[MatchMode]bool{
+			MatchContains: tt.contains,
+			MatchPrefix:   tt.prefix,
+			MatchSuffix:   tt.suffix,
+			MatchFull:     tt.full,
+			MatchCompat:   tt.compat,
+		}
+		for mode, want := range expected {
+			if got := IsMatchWithMode(tt.s, tt.pattern, mode); got != want {
+				t.Errorf("IsMatchWithMode(%q, %q, %d) = %v; want %v", tt.s, tt.pattern, mode, got, want)
+			}
+			if got := MustCompile(tt.pattern).IsMatchWithMode(tt.s, mode); got != want {
+				t.Errorf("Pattern(%q).IsMatchWithMode(%q, %d) = %v; want %v", tt.pattern, tt.s, mode, got, want)
+			}
+		}
+	}
+}
+
+func TestIsMatchWithModeCompat(t *testing.T) {
+	// legacyIsMatch is IsMatch as it was before MatchMode existed
+	legacyIsMatch := func(s string, regex string) bool {
+		if !strings.HasPrefix(regex, "^") {
+			regex = ".*" + regex
+		}
+		if !strings.HasSuffix(regex, "$") {
+			regex = regex + ".*"
+		}
+		matched, _ := regexp.MatchString(regex, s)
+		return matched
+	}
+
+	inputs := []string{"hello world", "abc", "", "x\nab", "你好", "bx"}
+	patterns := []string{"world", "^hello", "o$", "a|b$", "^a|b", "(?i)ABC", "^$", ".*", "x$"}
+	for _, s := range inputs {
+		for _, pattern := range patterns {
+			want := legacyIsMatch(s, pattern)
+			if got := IsMatchWithMode(s, pattern, MatchCompat); got != want {
+				t.Errorf("IsMatchWithMode(%q, %q, MatchCompat) = %v; want %v", s, pattern, got, want)
+			}
+			if got := MustCompile(pattern).IsMatchWithMode(s, MatchCompat); got != want {
+				t.Errorf("Pattern(%q).IsMatchWithMode(%q, MatchCompat) = %v; want %v", pattern, s, got, want)
+			}
+			if got := IsMatch(s, pattern); got != want {
+				t.Errorf("IsMatch(%q, %q) = %v; want %v", s, pattern, got, want)
+			}
+		}
+	}
+}
+
+func TestIsMatchWithModeSkipsCache(t *testing.T) {
+	ClearRegexCache()
+
+	p := MustCompile("x")
+	for _, mode := range []MatchMode{MatchPrefix, MatchSuffix, MatchFull, MatchCompat} {
+		for range 2 {
+			if !p.IsMatchWithMode("x", mode) {
+				t.Errorf("Pattern(%q).IsMatchWithMode(%q, %d) = false; want true", "x", "x", mode)
+			}
+		}
+	}
+	if stats := GetRegexCacheStats(); stats.Hits != 0 || stats.Misses != 0 || stats.Size != 0 {
+		t.Errorf("GetRegexCacheStats() = %+v; want the cache untouched", stats)
+	}
+}
+
+func TestIsMatchWithModeLongInput(t *testing.T) {
+	s := strings.Repeat("a", 100000) + "b"
+	if !IsMatchWithMode(s, "b", MatchSuffix) {
+		t.Error("IsMatchWithMode with MatchSuffix on long input = false; want true")
+	}
+	if IsMatchWithMode(s, "a+", MatchFull) {
+		t.Error("IsMatchWithMode with MatchFull on long input = true; want false")
+	}
+}
+
+func TestIsMatchWithModeErrors(t *testing.T) {
+	for _, mode := range []MatchMode{MatchContains, MatchPrefix, MatchSuffix, MatchFull, MatchCompat} {
+		matched, err := IsMatchWithModeE("abc", "(", mode)
+		var patternErr *PatternError
+		if matched || !errors.As(err, &patternErr) || patternErr.Pattern != "(" {
+			t.Errorf("IsMatchWithModeE(%d) = %v, %v; want false and *PatternError for %q", mode, matched, err, "(")
+		}
+	}
+}
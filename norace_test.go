@@ -0,0 +1,5 @@
+//go:build !race
+
+package s
+
+const raceEnabled = false
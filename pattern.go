@@ -0,0 +1,331 @@
+package s
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Pattern is a compiled regular expression exposing the package's regex helpers as
+// methods. Compile a pattern once and reuse it to avoid recompiling on every call.
+//
+// A Pattern is safe for concurrent use by multiple goroutines.
+type Pattern struct {
+	re *regexp.Regexp
+
+	// modes holds the pattern rewritten for each MatchMode, built on first use
+	modes [MatchCompat + 1]struct {
+		once sync.Once
+		re   *regexp.Regexp
+	}
+}
+
+// Compile parses a regular expression and returns a Pattern that can be used to
+// match against text. If the expression does not compile, the error is a *PatternError.
+func Compile(pattern string) (*Pattern, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, &PatternError{Pattern: pattern, Err: err}
+	}
+	return &Pattern{re: re}, nil
+}
+
+// MustCompile is like Compile but panics if the expression cannot be parsed.
+func MustCompile(pattern string) *Pattern {
+	p, err := Compile(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// NewPattern wraps an already compiled regular expression.
+func NewPattern(re *regexp.Regexp) *Pattern {
+	return &Pattern{re: re}
+}
+
+// Regexp returns the underlying compiled regular expression.
+func (p *Pattern) Regexp() *regexp.Regexp {
+	return p.re
+}
+
+// String returns the source text used to compile the pattern.
+func (p *Pattern) String() string {
+	return p.re.String()
+}
+
+// IsMatch reports whether s contains any match of the pattern.
+func (p *Pattern) IsMatch(s string) bool {
+	return p.re.MatchString(s)
+}
+
+// Grep returns all full matches of the pattern in s, or an empty slice if there are none.
+func (p *Pattern) Grep(s string) []string {
+	// Find all matches
+	matches := p.re.FindAllString(s, -1)
+	if matches == nil {
+		return []string{}
+	}
+	return matches
+}
+
+// GrepGroup returns the specified group (by index or name) from each match of the
+// pattern in s. If the group is not found, an empty slice is returned.
+func (p *Pattern) GrepGroup(s string, group string) []string {
+	result, err := p.GrepGroupE(s, group)
+	if err != nil {
+		return []string{}
+	}
+	return result
+}
+
+// GrepGroupE is like GrepGroup but returns an error wrapping ErrUnknownGroup if the
+// group does not exist.
+func (p *Pattern) GrepGroupE(s string, group string) ([]string, error) {
+	groupIdx, err := p.groupIndex(group)
+	if err != nil {
+		return []string{}, err
+	}
+
+	// Find all matches
+	matches := p.re.FindAllStringSubmatch(s, -1)
+	if matches == nil {
+		return []string{}, nil
+	}
+
+	// Extract the specified group from each match
+	result := make([]string, 0, len(matches))
+	for _, match := range matches {
+		result = append(result, match[groupIdx])
+	}
+
+	return result, nil
+}
+
+// GrepGroups returns the groups of every match of the pattern in s, one map per
+// match. Each map holds every group under its index ("0" for the whole match,
+// "1" for the first group and so on) and named groups under their name as well.
+// A group that did not take part in the match is absent from the map, while a
+// group that matched the empty string maps to "". If there is no match, an empty
+// slice is returned.
+//
+// Example:
+//
+//	p := MustCompile(`(?P<key>\w+)=(\d+)?`)
+//	p.GrepGroups("a=1 b=")
+//	// Returns [{"0": "a=1", "1": "a", "key": "a", "2": "1"}, {"0": "b=", "1": "b", "key": "b"}]
+func (p *Pattern) GrepGroups(s string) []map[string]string {
+	locations := p.re.FindAllStringSubmatchIndex(s, -1)
+	result := make([]map[string]string, 0, len(locations))
+	for _, loc := range locations {
+		result = append(result, p.groupMap(s, loc))
+	}
+	return result
+}
+
+// MatchGroups is like GrepGroups for the first match of the pattern in s only.
+// It returns nil if there is no match.
+func (p *Pattern) MatchGroups(s string) map[string]string {
+	loc := p.re.FindStringSubmatchIndex(s)
+	if loc == nil {
+		return nil
+	}
+	return p.groupMap(s, loc)
+}
+
+// groupMap returns the groups of a match, given as submatch locations in s, by
+// index and name.
+func (p *Pattern) groupMap(s string, loc []int) map[string]string {
+	groups := make(map[string]string, len(loc)/2)
+	for i, name := range p.re.SubexpNames() {
+		start, end := loc[2*i], loc[2*i+1]
+		if start < 0 {
+			continue
+		}
+		groups[strconv.Itoa(i)] = s[start:end]
+		if name != "" {
+			groups[name] = s[start:end]
+		}
+	}
+	return groups
+}
+
+// Group returns the specified group (by index or name) from the first match of the
+// pattern in s, or "" if there is no match or the group does not exist.
+func (p *Pattern) Group(s string, group string) string {
+	result, _ := p.GroupE(s, group)
+	return result
+}
+
+// GroupE is like Group but returns an error wrapping ErrUnknownGroup if the group
+// does not exist.
+func (p *Pattern) GroupE(s string, group string) (string, error) {
+	groupIdx, err := p.groupIndex(group)
+	if err != nil {
+		return "", err
+	}
+
+	match := p.re.FindStringSubmatch(s)
+	if match == nil {
+		return "", nil
+	}
+
+	return match[groupIdx], nil
+}
+
+// groupIndex resolves group, given as a decimal index or a group name, to its
+// submatch index.
+func (p *Pattern) groupIndex(group string) (int, error) {
+	names := p.re.SubexpNames()
+
+	if i, err := strconv.Atoi(group); err == nil {
+		// Numeric group
+		if i >= 0 && i < len(names) {
+			return i, nil
+		}
+	} else {
+		// Named group
+		for i, name := range names {
+			if name == group {
+				return i, nil
+			}
+		}
+	}
+
+	return -1, fmt.Errorf("%w %q in pattern %q", ErrUnknownGroup, group, p.re.String())
+}
+
+// Highlight surrounds every match of the pattern in s with the left and right markers.
+// If there is no match, s is returned unchanged.
+func (p *Pattern) Highlight(s string, left string, right string) string {
+	matches := p.re.FindAllStringIndex(s, -1)
+	if len(matches) == 0 {
+		return s
+	}
+
+	var builder strings.Builder
+	lastPos := 0
+
+	// Pre-calculate the total size to avoid reallocations
+	totalSize := len(s) + (len(left)+len(right))*len(matches)
+	builder.Grow(totalSize)
+
+	// Process each match
+	for _, match := range matches {
+		start, end := match[0], match[1]
+
+		// Write the text between the last match and this match
+		builder.WriteString(s[lastPos:start])
+
+		// Write the highlight markers and the matched text
+		builder.WriteString(left)
+		builder.WriteString(s[start:end])
+		builder.WriteString(right)
+
+		lastPos = end
+	}
+
+	// Write any remaining text after the last match
+	if lastPos < len(s) {
+		builder.WriteString(s[lastPos:])
+	}
+
+	return builder.String()
+}
+
+// FilterOptions configures FilterLines. The zero value keeps the non-empty lines
+// that match the pattern, as MatchedLines does.
+type FilterOptions struct {
+	// KeepEmptyLines keeps empty lines that satisfy the filter, so that "^$"
+	// can match them. Otherwise empty lines are always dropped.
+	KeepEmptyLines bool
+	// Invert keeps the lines that do not match the pattern instead.
+	Invert bool
+	// MatchTrimmed matches the pattern against each line with leading and
+	// trailing whitespace removed, so whitespace-only lines count as empty.
+	// The kept lines are returned untrimmed.
+	MatchTrimmed bool
+	// KeepTrailingNewline ends the result with a line ending when s ends with
+	// one and at least one line is kept.
+	KeepTrailingNewline bool
+}
+
+// FilterLines keeps the lines of s selected by opts and joins them with the line
+// ending style of s.
+//
+// Example:
+//
+//	p := MustCompile(`^$`)
+//	p.FilterLines("a\n\nb\n", FilterOptions{KeepEmptyLines: true, Invert: true}) // Returns "a\nb"
+func (p *Pattern) FilterLines(s string, opts FilterOptions) string {
+	if s == "" {
+		return ""
+	}
+
+	// Detect original line ending style
+	lineEnding := GetLineEnding(s)
+
+	// The final line ending terminates the last line rather than starting a new one
+	trimmed := strings.TrimSuffix(s, "\n")
+	hasTrailingNewline := len(trimmed) < len(s)
+
+	// Filter lines
+	var kept []string
+	for _, line := range strings.SplitAfter(trimmed, "\n") {
+		line = strings.TrimRight(line, "\r\n")
+		if p.keepsLine(line, opts) {
+			kept = append(kept, line)
+		}
+	}
+
+	// Join the kept lines
+	result := strings.Join(kept, lineEnding)
+	if opts.KeepTrailingNewline && hasTrailingNewline && len(kept) > 0 {
+		result += lineEnding
+	}
+	return result
+}
+
+// keepsLine reports whether FilterLines keeps line, given without its line ending.
+func (p *Pattern) keepsLine(line string, opts FilterOptions) bool {
+	if opts.MatchTrimmed {
+		line = Trim(line)
+	}
+	if line == "" && !opts.KeepEmptyLines {
+		return false
+	}
+	return p.re.MatchString(line) != opts.Invert
+}
+
+// keepsLineBytes is like keepsLine for a line held in a byte slice, which is
+// matched in place without copying it to a string.
+func (p *Pattern) keepsLineBytes(line []byte, opts FilterOptions) bool {
+	if opts.MatchTrimmed {
+		line = bytes.Trim(line, WhiteSpace)
+	}
+	if len(line) == 0 && !opts.KeepEmptyLines {
+		return false
+	}
+	return p.re.Match(line) != opts.Invert
+}
+
+// MatchedLines keeps the non-empty lines of s that match the pattern and joins them
+// with the line ending style of s. A pattern compiled from "" returns "".
+func (p *Pattern) MatchedLines(s string) string {
+	if p.re.String() == "" {
+		return ""
+	}
+	return p.FilterLines(s, FilterOptions{})
+}
+
+// UnmatchedLines keeps the non-empty lines of s that do not match the pattern and
+// joins them with the line ending style of s. A pattern compiled from "" returns "".
+func (p *Pattern) UnmatchedLines(s string) string {
+	if p.re.String() == "" {
+		return ""
+	}
+	return p.FilterLines(s, FilterOptions{Invert: true})
+}
@@ -0,0 +1,105 @@
+package s
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestPatternMatchesPackageFunctions(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		pattern string
+		group   string
+	}{
+		{"numbered group", "user=alice id=12\nuser=bob id=34\n", `user=(\w+)`, "1"},
+		{"named group", "user=alice id=12\r\nuser=bob id=34", `id=(?P<id>\d+)`, "id"},
+		{"no match", "hello world", `\d+`, "0"},
+		{"unknown group", "hello world", `(o)`, "2"},
+		{"empty lines", "apple\n\nbanana\n\ncherry", `^[ab]`, "0"},
+		{"unicode", "你好世界\n世界", `世界`, "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := MustCompile(tt.pattern)
+
+			if got, want := p.IsMatch(tt.s), IsMatch(tt.s, tt.pattern); got != want {
+				t.Errorf("IsMatch = %v; want %v", got, want)
+			}
+			if got, want := p.Grep(tt.s), Grep(tt.s, tt.pattern); !reflect.DeepEqual(got, want) {
+				t.Errorf("Grep = %q; want %q", got, want)
+			}
+			if got, want := p.GrepGroup(tt.s, tt.group), GrepGroup(tt.s, tt.pattern, tt.group); !reflect.DeepEqual(got, want) {
+				t.Errorf("GrepGroup = %q; want %q", got, want)
+			}
+			if got, want := p.Group(tt.s, tt.group), GetMatchedRegexGroup(tt.s, tt.pattern, tt.group); got != want {
+				t.Errorf("Group = %q; want %q", got, want)
+			}
+			if got, want := p.Highlight(tt.s, "[", "]"), Highlight(tt.s, tt.pattern, "[", "]"); got != want {
+				t.Errorf("Highlight = %q; want %q", got, want)
+			}
+			if got, want := p.GrepGroups(tt.s), GrepGroups(tt.s, tt.pattern); !reflect.DeepEqual(got, want) {
+				t.Errorf("GrepGroups = %v; want %v", got, want)
+			}
+			if got, want := p.MatchGroups(tt.s), MatchGroups(tt.s, tt.pattern); !reflect.DeepEqual(got, want) {
+				t.Errorf("MatchGroups = %v; want %v", got, want)
+			}
+			if got, want := p.MatchedLines(tt.s), GetRegexMatchedLinesAsString(tt.s, tt.pattern); got != want {
+				t.Errorf("MatchedLines = %q; want %q", got, want)
+			}
+			if got, want := p.UnmatchedLines(tt.s), GetRegexUnmatchedLinesAsString(tt.s, tt.pattern); got != want {
+				t.Errorf("UnmatchedLines = %q; want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestCompile(t *testing.T) {
+	p, err := Compile(`a(b)`)
+	if err != nil {
+		t.Fatalf("Compile(%q) returned error: %v", `a(b)`, err)
+	}
+	if p.String() != `a(b)` || p.Regexp().NumSubexp() != 1 {
+		t.Errorf("Compile(%q) = %v", `a(b)`, p)
+	}
+
+	_, err = Compile(`a(b`)
+	var patternErr *PatternError
+	if !errors.As(err, &patternErr) || patternErr.Pattern != `a(b` {
+		t.Errorf("Compile(%q) error = %v; want *PatternError", `a(b`, err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MustCompile(%q) did not panic", `a(b`)
+		}
+	}()
+	MustCompile(`a(b`)
+}
+
+func TestPatternConcurrent(t *testing.T) {
+	p := MustCompile(`(?P<key>\w+)=(?P<value>\d+)`)
+	s := "a=1 b=2 c=3"
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if got := p.GrepGroup(s, "value"); !reflect.DeepEqual(got, []string{"1", "2", "3"}) {
+					t.Errorf("GrepGroup = %q", got)
+					return
+				}
+				if got := p.Highlight(s, "<", ">"); got != "<a=1> <b=2> <c=3>" {
+					t.Errorf("Highlight = %q", got)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
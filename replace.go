@@ -0,0 +1,156 @@
+package s
+
+import (
+	"strings"
+)
+
+// Replace replaces every match of the pattern in s with template, in which $1 or
+// ${1} stands for the text of the first group and $name or ${name} for the text
+// of the named group, as in regexp.Regexp.Expand. Use ${1} when the reference is
+// followed by a letter, digit or underscore: "$1x" refers to a group named "1x".
+// A literal $ is written $$.
+//
+// Example:
+//
+//	p := MustCompile(`(?P<key>\w+)=(\w+)`)
+//	p.Replace("a=1 b=2", "${2}:$key") // Returns "1:a 2:b"
+func (p *Pattern) Replace(s string, template string) string {
+	result, _ := p.ReplaceN(s, template, -1)
+	return result
+}
+
+// ReplaceN is like Replace but replaces at most n matches, all of them if n < 0,
+// and also returns the number of replacements made.
+func (p *Pattern) ReplaceN(s string, template string, n int) (string, int) {
+	locations := p.re.FindAllStringSubmatchIndex(s, n)
+	if len(locations) == 0 {
+		return s, 0
+	}
+
+	var result []byte
+	lastPos := 0
+	for _, loc := range locations {
+		// Copy the text between the last match and this match
+		result = append(result, s[lastPos:loc[0]]...)
+		result = p.re.ExpandString(result, template, s, loc)
+		lastPos = loc[1]
+	}
+	result = append(result, s[lastPos:]...)
+
+	return string(result), len(locations)
+}
+
+// ReplaceFunc replaces every match of the pattern in s with the result of calling
+// replace with the match, which describes its text, groups and position.
+//
+// Example:
+//
+//	p := MustCompile(`(?P<n>\d+)`)
+//	p.ReplaceFunc("a1 b22", func(m Match) string {
+//		return strconv.Itoa(len(m.Named["n"].Text))
+//	}) // Returns "a1 b2"
+func (p *Pattern) ReplaceFunc(s string, replace func(match Match) string) string {
+	result, _ := p.ReplaceFuncN(s, replace, -1)
+	return result
+}
+
+// ReplaceFuncN is like ReplaceFunc but replaces at most n matches, all of them if
+// n < 0, and also returns the number of replacements made.
+func (p *Pattern) ReplaceFuncN(s string, replace func(match Match) string, n int) (string, int) {
+	locations := p.re.FindAllStringSubmatchIndex(s, n)
+	if len(locations) == 0 {
+		return s, 0
+	}
+
+	var builder strings.Builder
+	lastPos := 0
+	for i, match := range p.buildMatches(s, locations) {
+		builder.WriteString(s[lastPos:locations[i][0]])
+		builder.WriteString(replace(match))
+		lastPos = locations[i][1]
+	}
+	builder.WriteString(s[lastPos:])
+
+	return builder.String(), len(locations)
+}
+
+// Replace replaces every match of pattern in s with template, expanding group
+// references such as $1 and ${name}. See Pattern.Replace. If the pattern is
+// invalid, s is returned unchanged.
+func Replace(s string, pattern string, template string) string {
+	result, err := ReplaceE(s, pattern, template)
+	if err != nil {
+		return s
+	}
+	return result
+}
+
+// ReplaceE is like Replace but returns a *PatternError if the pattern does not compile.
+func ReplaceE(s string, pattern string, template string) (string, error) {
+	p, err := compilePattern(pattern)
+	if err != nil {
+		return s, err
+	}
+	return p.Replace(s, template), nil
+}
+
+// ReplaceN is like Replace but replaces at most n matches, all of them if n < 0,
+// and also returns the number of replacements made. If the pattern is invalid, s
+// is returned unchanged with a count of 0; use ReplaceNE to tell this apart from
+// no match.
+func ReplaceN(s string, pattern string, template string, n int) (string, int) {
+	result, count, _ := ReplaceNE(s, pattern, template, n)
+	return result, count
+}
+
+// ReplaceNE is like ReplaceN but returns a *PatternError if the pattern does not
+// compile.
+func ReplaceNE(s string, pattern string, template string, n int) (string, int, error) {
+	p, err := compilePattern(pattern)
+	if err != nil {
+		return s, 0, err
+	}
+	result, count := p.ReplaceN(s, template, n)
+	return result, count, nil
+}
+
+// ReplaceFunc replaces every match of pattern in s with the result of calling
+// replace with the match. See Pattern.ReplaceFunc. If the pattern is invalid, s is
+// returned unchanged.
+func ReplaceFunc(s string, pattern string, replace func(match Match) string) string {
+	result, err := ReplaceFuncE(s, pattern, replace)
+	if err != nil {
+		return s
+	}
+	return result
+}
+
+// ReplaceFuncE is like ReplaceFunc but returns a *PatternError if the pattern does
+// not compile.
+func ReplaceFuncE(s string, pattern string, replace func(match Match) string) (string, error) {
+	p, err := compilePattern(pattern)
+	if err != nil {
+		return s, err
+	}
+	return p.ReplaceFunc(s, replace), nil
+}
+
+// ReplaceFuncN is like ReplaceFunc but replaces at most n matches, all of them if
+// n < 0, and also returns the number of replacements made. If the pattern is
+// invalid, s is returned unchanged with a count of 0; use ReplaceFuncNE to tell
+// this apart from no match.
+func ReplaceFuncN(s string, pattern string, replace func(match Match) string, n int) (string, int) {
+	result, count, _ := ReplaceFuncNE(s, pattern, replace, n)
+	return result, count
+}
+
+// ReplaceFuncNE is like ReplaceFuncN but returns a *PatternError if the pattern
+// does not compile.
+func ReplaceFuncNE(s string, pattern string, replace func(match Match) string, n int) (string, int, error) {
+	p, err := compilePattern(pattern)
+	if err != nil {
+		return s, 0, err
+	}
+	result, count := p.ReplaceFuncN(s, replace, n)
+	return result, count, nil
+}
@@ -0,0 +1,145 @@
+package s
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestReplace(t *testing.T) {
+	tests := []struct {
+		input    string
+		pattern  string
+		template string
+		expected string
+	}{
+		{"a=1 b=2", `(?P<key>\w+)=(\w+)`, "${2}:$key", "1:a 2:b"},
+		{"a=1 b=2", `(\w+)=(\w+)`, "$2=$1", "1=a 2=b"},
+		{"a=1", `(\w+)=(\w+)`, "$1x", ""},
+		{"a=1", `(\w+)=(\w+)`, "${1}x", "ax"},
+		{"price 5", `(\d+)`, "$$$1", "price $5"},
+		{"a=1", `(\w+)=(\w+)`, "$3$missing", ""},
+		{"no match", `\d+`, "#", "no match"},
+		{"abc", ``, "-", "-a-b-c-"},
+		{"你好世界", `(世)(界)`, "$2$1", "你好界世"},
+		{"abc", `(`, "x", "abc"},
+	}
+
+	for _, test := range tests {
+		result := Replace(test.input, test.pattern, test.template)
+		if result != test.expected {
+			t.Errorf("Replace(%q, %q, %q) = %q; want %q",
+				test.input, test.pattern, test.template, result, test.expected)
+		}
+	}
+}
+
+func TestReplaceN(t *testing.T) {
+	tests := []struct {
+		n        int
+		expected string
+		count    int
+	}{
+		{-1, "x x x", 3},
+		{0, "1 2 3", 0},
+		{2, "x x 3", 2},
+		{5, "x x x", 3},
+	}
+
+	for _, test := range tests {
+		result, count := ReplaceN("1 2 3", `\d`, "x", test.n)
+		if result != test.expected || count != test.count {
+			t.Errorf("ReplaceN(%d) = %q, %d; want %q, %d",
+				test.n, result, count, test.expected, test.count)
+		}
+	}
+
+	if result, count := ReplaceN("abc", `(`, "x", -1); result != "abc" || count != 0 {
+		t.Errorf("ReplaceN with invalid pattern = %q, %d; want %q, 0", result, count, "abc")
+	}
+}
+
+func TestReplaceFunc(t *testing.T) {
+	var lines []int
+	result := ReplaceFunc("x=1\ny=22\nz", `(?P<key>\w)=(?P<value>\d+)`, func(m Match) string {
+		lines = append(lines, m.Start.Line)
+		n, _ := strconv.Atoi(m.Named["value"].Text)
+		return fmt.Sprintf("%s:%d", strings.ToUpper(m.Groups[1].Text), n*2)
+	})
+	if result != "X:2\nY:44\nz" {
+		t.Errorf("ReplaceFunc = %q; want %q", result, "X:2\nY:44\nz")
+	}
+	if fmt.Sprint(lines) != "[1 2]" {
+		t.Errorf("ReplaceFunc saw lines %v; want [1 2]", lines)
+	}
+
+	optional := ReplaceFunc("ab b", `(a)?b`, func(m Match) string {
+		if !m.Groups[1].Matched {
+			return "<none>"
+		}
+		return "<" + m.Groups[1].Text + ">"
+	})
+	if optional != "<a> <none>" {
+		t.Errorf("ReplaceFunc with optional group = %q; want %q", optional, "<a> <none>")
+	}
+
+	result, count := ReplaceFuncN("a b c", `\w`, func(m Match) string {
+		return strconv.Itoa(m.Start.Column)
+	}, 2)
+	if result != "1 3 c" || count != 2 {
+		t.Errorf("ReplaceFuncN = %q, %d; want %q, 2", result, count, "1 3 c")
+	}
+
+	called := false
+	if result := ReplaceFunc("abc", `\d`, func(Match) string { called = true; return "" }); result != "abc" || called {
+		t.Errorf("ReplaceFunc with no match = %q (called %v); want %q", result, called, "abc")
+	}
+}
+
+func TestReplaceErrors(t *testing.T) {
+	var patternErr *PatternError
+
+	result, err := ReplaceE("abc", `(`, "x")
+	if result != "abc" || !errors.As(err, &patternErr) {
+		t.Errorf("ReplaceE with invalid pattern = %q, %v; want %q and *PatternError", result, err, "abc")
+	}
+
+	result, err = ReplaceFuncE("abc", `(`, func(Match) string { return "x" })
+	if result != "abc" || !errors.As(err, &patternErr) {
+		t.Errorf("ReplaceFuncE with invalid pattern = %q, %v; want %q and *PatternError", result, err, "abc")
+	}
+	if result := ReplaceFunc("abc", `(`, func(Match) string { return "x" }); result != "abc" {
+		t.Errorf("ReplaceFunc with invalid pattern = %q; want %q", result, "abc")
+	}
+
+	result, count, err := ReplaceNE("abc", `(`, "x", -1)
+	if result != "abc" || count != 0 || !errors.As(err, &patternErr) {
+		t.Errorf("ReplaceNE with invalid pattern = %q, %d, %v; want %q, 0 and *PatternError", result, count, err, "abc")
+	}
+	result, count, err = ReplaceFuncNE("abc", `(`, func(Match) string { return "x" }, -1)
+	if result != "abc" || count != 0 || !errors.As(err, &patternErr) {
+		t.Errorf("ReplaceFuncNE with invalid pattern = %q, %d, %v; want %q, 0 and *PatternError", result, count, err, "abc")
+	}
+
+	// A valid pattern without matches reports no error
+	result, count, err = ReplaceNE("abc", `\d`, "x", -1)
+	if result != "abc" || count != 0 || err != nil {
+		t.Errorf("ReplaceNE with no match = %q, %d, %v; want %q, 0, nil", result, count, err, "abc")
+	}
+	result, count, err = ReplaceFuncNE("a1b2", `\d`, func(Match) string { return "#" }, 1)
+	if result != "a#b2" || count != 1 || err != nil {
+		t.Errorf("ReplaceFuncNE = %q, %d, %v; want %q, 1, nil", result, count, err, "a#b2")
+	}
+}
+
+func TestReplaceMatchesRegexp(t *testing.T) {
+	p := MustCompile(`a*`)
+	inputs := []string{"baaac", "aaa", "", "b"}
+	for _, input := range inputs {
+		if got, want := p.Replace(input, "[$0]"), p.Regexp().ReplaceAllString(input, "[$0]"); got != want {
+			t.Errorf("Replace(%q) = %q; regexp returned %q", input, got, want)
+		}
+	}
+}
@@ -1,11 +1,13 @@
 package s
 
 import (
+	"iter"
 	"math"
 	"regexp"
-	"strconv"
+	"slices"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 const WhiteSpace = "\t\n\r "
@@ -34,6 +36,47 @@ func RStrip(s string) string {
 	return TrimRight(s)
 }
 
+// ZeroWidth lists invisible characters that Unicode doesn't class as spaces but that
+// commonly survive copy and paste: ZERO WIDTH SPACE, WORD JOINER, the byte order mark
+// ZERO WIDTH NO-BREAK SPACE and MONGOLIAN VOWEL SEPARATOR. Pass it as an extra set to
+// TrimSpace and friends.
+const ZeroWidth = "\u200B\u2060\uFEFF\u180E"
+
+// isSpaceFunc returns a predicate matching unicode.IsSpace and any rune in extra.
+func isSpaceFunc(extra []string) func(rune) bool {
+	set := strings.Join(extra, "")
+	return func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(set, r)
+	}
+}
+
+// TrimSpace is a Unicode-aware Trim: it removes leading and trailing white space as
+// defined by unicode.IsSpace (including NBSP, U+3000 IDEOGRAPHIC SPACE, vertical tab
+// and form feed), plus any rune in the extra sets, e.g. ZeroWidth.
+func TrimSpace(s string, extra ...string) string {
+	return strings.TrimFunc(s, isSpaceFunc(extra))
+}
+
+// TrimLeftSpace is like TrimSpace but only removes leading white space.
+func TrimLeftSpace(s string, extra ...string) string {
+	return strings.TrimLeftFunc(s, isSpaceFunc(extra))
+}
+
+// TrimRightSpace is like TrimSpace but only removes trailing white space.
+func TrimRightSpace(s string, extra ...string) string {
+	return strings.TrimRightFunc(s, isSpaceFunc(extra))
+}
+
+// CollapseWhitespace trims s like TrimSpace and replaces every internal run of white
+// space, including line breaks, with a single space.
+//
+// Example:
+//
+//	CollapseWhitespace(" a \t b\u3000\u3000c\n") // Returns "a b c"
+func CollapseWhitespace(s string, extra ...string) string {
+	return strings.Join(strings.FieldsFunc(s, isSpaceFunc(extra)), " ")
+}
+
 func GetIndentString(s string) int {
 	// Normalize all line endings to \n
 	s = ToLinuxLineEnding(s)
@@ -76,9 +119,10 @@ func ToLinuxLineEnding(s string) string {
 	return strings.ReplaceAll(s, "\r\n", "\n")
 }
 
+var lineEndingRegex = regexp.MustCompile(`\r?\n`)
+
 func ToWindowsLineEnding(s string) string {
-	reg := regexp.MustCompile(`\r?\n`)
-	return reg.ReplaceAllString(s, "\r\n")
+	return lineEndingRegex.ReplaceAllString(s, "\r\n")
 }
 
 // - A string with the common leading whitespace removed from each line and original line ending style restored.
@@ -203,6 +247,148 @@ func Succ(s string) string {
 	return string(runes)
 }
 
+// Pred returns the predecessor of s, reversing Succ: the rightmost alphanumeric
+// character is decremented, borrowing from the characters to its left ("b0" becomes
+// "a9"), and a leading character that Succ would have added on carry is dropped
+// ("aa" becomes "z", "100" becomes "99"). Strings without alphanumerics have their
+// last character decremented.
+func Pred(s string) string {
+	if len(s) == 0 {
+		return s
+	}
+
+	runes := []rune(s)
+
+	// Find the rightmost character to decrement, as Succ does
+	lastAlphaNumPos := -1
+	for i := len(runes) - 1; i >= 0; i-- {
+		if unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) {
+			lastAlphaNumPos = i
+			break
+		}
+	}
+
+	if lastAlphaNumPos == -1 {
+		// No alphanumeric found, decrement the last character
+		runes[len(runes)-1]--
+		return string(runes)
+	}
+
+	pos := lastAlphaNumPos
+	borrow := true
+
+	for pos >= 0 && borrow {
+		r := runes[pos]
+
+		// Drop the character Succ adds when carrying beyond the leftmost position
+		if pos == 0 && lastAlphaNumPos > 0 && r == succCarryPrefix(runes[1]) {
+			return string(runes[1:])
+		}
+
+		if unicode.IsDigit(r) {
+			if r == '0' {
+				runes[pos] = '9'
+				borrow = true
+			} else {
+				runes[pos]--
+				borrow = false
+			}
+		} else if unicode.IsLetter(r) {
+			if r == 'A' {
+				runes[pos] = 'Z'
+				borrow = true
+			} else if r == 'a' {
+				runes[pos] = 'z'
+				borrow = true
+			} else {
+				runes[pos]--
+				borrow = false
+			}
+		} else {
+			// For non-alphanumeric characters
+			runes[pos]--
+			borrow = false
+		}
+		pos--
+	}
+
+	if borrow {
+		// Nothing left to borrow from: this is the smallest value of its form, so
+		// reverse a Succ that incremented a non-alphanumeric character instead
+		// ("a" becomes "`", "A0" becomes "@9").
+		switch r := runes[0]; {
+		case r == '9':
+			runes[0] = '0'
+		case r == 'Z':
+			runes[0] = 'A'
+		case r == 'z':
+			runes[0] = 'a'
+		}
+		runes[0]--
+	}
+
+	return string(runes)
+}
+
+// succCarryPrefix returns the character Succ prepends when it carries beyond the
+// leftmost position of a string that now starts with r.
+func succCarryPrefix(r rune) rune {
+	if unicode.IsDigit(r) {
+		return '1'
+	}
+	if unicode.IsUpper(r) {
+		return 'A'
+	}
+	if unicode.IsLetter(r) {
+		return 'a'
+	}
+	return -1
+}
+
+// DefaultSuccRangeLimit is the number of values SuccSeq yields at most when no
+// limit is given.
+const DefaultSuccRangeLimit = 1000000
+
+// SuccSeq returns an iterator over from, Succ(from), Succ(Succ(from)) and so on up
+// to and including to. Like Ruby's String#upto, it yields nothing if from is longer
+// than to, or as long and sorts after it, and it stops once the values grow longer
+// than to. At most limit values are yielded; a limit <= 0 means
+// DefaultSuccRangeLimit.
+func SuccSeq(from, to string, limit int) iter.Seq[string] {
+	if limit <= 0 {
+		limit = DefaultSuccRangeLimit
+	}
+
+	return func(yield func(string) bool) {
+		toLen := LenRune(to)
+		fromLen := LenRune(from)
+		if fromLen > toLen || (fromLen == toLen && from > to) {
+			return
+		}
+
+		current := from
+		for i := 0; i < limit; i++ {
+			if !yield(current) || current == to {
+				return
+			}
+			next := Succ(current)
+			if next == current || LenRune(next) > toLen {
+				return
+			}
+			current = next
+		}
+	}
+}
+
+// SuccRange returns the values yielded by SuccSeq(from, to, 0) as a slice.
+//
+// Example:
+//
+//	SuccRange("a8", "b1") // Returns ["a8", "a9", "b0", "b1"]
+func SuccRange(from, to string) []string {
+	return slices.Collect(SuccSeq(from, to, 0))
+}
+
 // Highlight highlights all occurrences of a pattern in a given string by surrounding them with specified left and right markers.
 //
 // Parameters:
@@ -215,44 +401,20 @@ func Succ(s string) string {
 //
 //	A new string with all occurrences of the pattern surrounded by the left and right markers. If the pattern is not found or if there is an error compiling the pattern, the original string is returned.
 func Highlight(s string, pattern string, left string, right string) string {
-	re, err := regexp.Compile(pattern)
+	result, err := HighlightE(s, pattern, left, right)
 	if err != nil {
 		return s
 	}
+	return result
+}
 
-	matches := re.FindAllIndex([]byte(s), -1)
-	if len(matches) == 0 {
-		return s
-	}
-
-	var builder strings.Builder
-	lastPos := 0
-
-	// Pre-calculate the total size to avoid reallocations
-	totalSize := len(s) + (len(left)+len(right))*len(matches)
-	builder.Grow(totalSize)
-
-	// Process each match
-	for _, match := range matches {
-		start, end := match[0], match[1]
-
-		// Write the text between the last match and this match
-		builder.WriteString(s[lastPos:start])
-
-		// Write the highlight markers and the matched text
-		builder.WriteString(left)
-		builder.WriteString(s[start:end])
-		builder.WriteString(right)
-
-		lastPos = end
-	}
-
-	// Write any remaining text after the last match
-	if lastPos < len(s) {
-		builder.WriteString(s[lastPos:])
+// HighlightE is like Highlight but returns a *PatternError if the pattern does not compile.
+func HighlightE(s string, pattern string, left string, right string) (string, error) {
+	p, err := compilePattern(pattern)
+	if err != nil {
+		return s, err
 	}
-
-	return builder.String()
+	return p.Highlight(s, left, right), nil
 }
 
 func Indent(s string, indent string) string {
@@ -344,6 +506,7 @@ func sumLength(strings []string) int {
 // IsMatch checks if the given string `s` matches the provided regular expression `regex`.
 // The function modifies the regex to allow partial matches if it doesn't start with `^` or end with `$`.
 // It returns true if the string matches the modified regex, otherwise false.
+// Use IsMatchWithMode to match without rewriting the regex.
 //
 // Parameters:
 //   - s: The string to be matched against the regex.
@@ -352,22 +515,13 @@ func sumLength(strings []string) int {
 // Returns:
 //   - bool: True if the string matches the regex, false otherwise.
 func IsMatch(s string, regex string) bool {
-	// If regex doesn't start with ^, allow partial matches from start
-	if !strings.HasPrefix(regex, "^") {
-		regex = ".*" + regex
-	}
-
-	// If regex doesn't end with $, allow partial matches at end
-	if !strings.HasSuffix(regex, "$") {
-		regex = regex + ".*"
-	}
-
-	re, err := regexp.Compile(regex)
-	if err != nil {
-		return false
-	}
+	matched, _ := IsMatchE(s, regex)
+	return matched
+}
 
-	return re.MatchString(s)
+// IsMatchE is like IsMatch but returns a *PatternError if the regex does not compile.
+func IsMatchE(s string, regex string) (bool, error) {
+	return IsMatchWithModeE(s, regex, MatchCompat)
 }
 
 // Grep searches for all occurrences of the given pattern in the input string s
@@ -382,24 +536,20 @@ func IsMatch(s string, regex string) bool {
 //	A slice of strings containing all full matches of the pattern in the input string.
 //	If no matches are found or if the pattern is invalid, an empty slice is returned.
 func Grep(s string, pattern string) []string {
-	re, err := regexp.Compile(pattern)
+	result, err := GrepE(s, pattern)
 	if err != nil {
 		return []string{}
 	}
+	return result
+}
 
-	// Find all matches
-	matches := re.FindAllStringSubmatch(s, -1)
-	if matches == nil {
-		return []string{}
-	}
-
-	// Extract the full matches
-	result := make([]string, 0, len(matches))
-	for _, match := range matches {
-		result = append(result, match[0])
+// GrepE is like Grep but returns a *PatternError if the pattern does not compile.
+func GrepE(s string, pattern string) ([]string, error) {
+	p, err := compilePattern(pattern)
+	if err != nil {
+		return []string{}, err
 	}
-
-	return result
+	return p.Grep(s), nil
 }
 
 // GrepGroup searches the input string `s` for all matches of the regular expression `pattern`
@@ -420,81 +570,80 @@ func Grep(s string, pattern string) []string {
 //	matches := GrepGroup("example123test456", `(\d+)`, "1")
 //	// matches will contain ["123", "456"]
 func GrepGroup(s string, pattern string, group string) []string {
-	re, err := regexp.Compile(pattern)
+	result, err := GrepGroupE(s, pattern, group)
 	if err != nil {
 		return []string{}
 	}
+	return result
+}
 
-	// Find all matches
-	matches := re.FindAllStringSubmatch(s, -1)
-	if matches == nil {
-		return []string{}
-	}
-
-	// Get group names if pattern has named groups
-	groupNames := re.SubexpNames()
-
-	// Determine group index
-	groupIdx := -1
-	if i, err := strconv.Atoi(group); err == nil {
-		// Numeric group
-		if i >= 0 && i < len(groupNames) {
-			groupIdx = i
-		}
-	} else {
-		// Named group
-		for i, name := range groupNames {
-			if name == group {
-				groupIdx = i
-				break
-			}
-		}
+// GrepGroupE is like GrepGroup but returns a *PatternError if the pattern does not
+// compile and an error wrapping ErrUnknownGroup if the group does not exist.
+func GrepGroupE(s string, pattern string, group string) ([]string, error) {
+	p, err := compilePattern(pattern)
+	if err != nil {
+		return []string{}, err
 	}
+	return p.GrepGroupE(s, group)
+}
 
-	// Return empty if group not found
-	if groupIdx == -1 {
-		return []string{}
+// GrepGroups returns the groups of every match of pattern in s by index and name,
+// one map per match. A group that did not take part in a match is absent from its
+// map, while a group that matched the empty string maps to "". See
+// Pattern.GrepGroups. If there is no match or the pattern is invalid, an empty
+// slice is returned.
+func GrepGroups(s string, pattern string) []map[string]string {
+	result, err := GrepGroupsE(s, pattern)
+	if err != nil {
+		return []map[string]string{}
 	}
+	return result
+}
 
-	// Extract the specified group from each match
-	result := make([]string, 0, len(matches))
-	for _, match := range matches {
-		if groupIdx < len(match) {
-			result = append(result, match[groupIdx])
-		}
+// GrepGroupsE is like GrepGroups but returns a *PatternError if the pattern does
+// not compile.
+func GrepGroupsE(s string, pattern string) ([]map[string]string, error) {
+	p, err := compilePattern(pattern)
+	if err != nil {
+		return []map[string]string{}, err
 	}
+	return p.GrepGroups(s), nil
+}
 
+// MatchGroups returns the groups of the first match of pattern in s by index and
+// name, like GrepGroups. If there is no match or the pattern is invalid, nil is
+// returned.
+func MatchGroups(s string, pattern string) map[string]string {
+	result, _ := MatchGroupsE(s, pattern)
 	return result
 }
 
-func GetMatchedRegexGroup(s, pattern, group string) string {
-	re, err := regexp.Compile(pattern)
+// MatchGroupsE is like MatchGroups but returns a *PatternError if the pattern does
+// not compile.
+func MatchGroupsE(s string, pattern string) (map[string]string, error) {
+	p, err := compilePattern(pattern)
 	if err != nil {
-		return ""
-	}
-
-	match := re.FindStringSubmatch(s)
-	if match == nil {
-		return ""
+		return nil, err
 	}
+	return p.MatchGroups(s), nil
+}
 
-	// If group is a number
-	if groupNum, err := strconv.Atoi(group); err == nil {
-		if groupNum < 0 || groupNum >= len(match) {
-			return ""
-		}
-		return match[groupNum]
-	}
+// GetMatchedRegexGroup returns the specified group (by index or name) from the first
+// match of pattern in s, or "" if there is no match, the group does not exist, or the
+// pattern does not compile.
+func GetMatchedRegexGroup(s, pattern, group string) string {
+	result, _ := GetMatchedRegexGroupE(s, pattern, group)
+	return result
+}
 
-	// If group is a name
-	names := re.SubexpNames()
-	for i, name := range names {
-		if name == group && i < len(match) {
-			return match[i]
-		}
+// GetMatchedRegexGroupE is like GetMatchedRegexGroup but returns a *PatternError if the
+// pattern does not compile and an error wrapping ErrUnknownGroup if the group does not exist.
+func GetMatchedRegexGroupE(s, pattern, group string) (string, error) {
+	p, err := compilePattern(pattern)
+	if err != nil {
+		return "", err
 	}
-
-	return ""
+	return p.GroupE(s, group)
 }
 
 // Repeat returns s repeated count times (concatenated).
@@ -516,59 +665,125 @@ func Repeat(s string, count int) string {
 
 // LeftPad: repeat padStr on the left. If final exceeds length, trim from the right.
 func LeftPad(s string, padStr string, length int) string {
-	runes := []rune(s)
-	if len(runes) >= length || padStr == "" {
-		return s
-	}
-	needed := length - len(runes)
-	prepend := Repeat(padStr, needed)
-	// Grab "needed" runes from the beginning of prepend
-	result := []rune(prepend)[:needed]
-	result = append(result, runes...)
-	return string(result)
+	return leftPadUnits(s, padStr, length, runeUnits)
 }
 
 // RightPad: repeat padStr on the right.
 func RightPad(s string, padStr string, length int) string {
-	runes := []rune(s)
-	if len(runes) >= length || padStr == "" {
-		return s
-	}
-	needed := length - len(runes)
-	appendStr := Repeat(padStr, needed)
-	// Grab "needed" runes from the end of appendStr
-	result := append(runes, []rune(appendStr)[:needed]...)
-	return string(result)
+	return rightPadUnits(s, padStr, length, runeUnits)
 }
 
 // Pad: two-sided padding in a stepwise manner (left first, then right, then left, etc.).
 func Pad(s string, padStr string, length int) string {
+	return padUnits(s, padStr, length, runeUnits)
+}
+
+// LeftPadGrapheme is like LeftPad but measures s, padStr and length in grapheme clusters.
+func LeftPadGrapheme(s string, padStr string, length int) string {
+	return leftPadUnits(s, padStr, length, graphemeUnits)
+}
+
+// RightPadGrapheme is like RightPad but measures s, padStr and length in grapheme clusters.
+func RightPadGrapheme(s string, padStr string, length int) string {
+	return rightPadUnits(s, padStr, length, graphemeUnits)
+}
+
+// PadGrapheme is like Pad but measures s, padStr and length in grapheme clusters.
+func PadGrapheme(s string, padStr string, length int) string {
+	return padUnits(s, padStr, length, graphemeUnits)
+}
+
+// splitRunes splits s into one string per rune.
+func splitRunes(s string) []string {
 	runes := []rune(s)
-	s_len := len(runes)
-	if len(runes) >= length || padStr == "" {
+	result := make([]string, len(runes))
+	for i, r := range runes {
+		result[i] = string(r)
+	}
+	return result
+}
+
+// textUnits describes the characters the padding functions count: count returns
+// the number of characters in s and next the length in bytes of the first one.
+type textUnits struct {
+	count func(s string) int
+	next  func(s string) int
+}
+
+var (
+	runeUnits = textUnits{
+		count: utf8.RuneCountInString,
+		next: func(s string) int {
+			_, size := utf8.DecodeRuneInString(s)
+			return size
+		},
+	}
+	graphemeUnits = textUnits{count: LenGrapheme, next: nextGrapheme}
+)
+
+// fill returns the first n characters of padStr repeated.
+func (u textUnits) fill(padStr string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	repeated := Repeat(padStr, n)
+	end := 0
+	for i := 0; i < n && end < len(repeated); i++ {
+		end += u.next(repeated[end:])
+	}
+	return repeated[:end]
+}
+
+// leftPadUnits pads s on the left, counting characters as units.
+func leftPadUnits(s string, padStr string, length int, units textUnits) string {
+	sLen := units.count(s)
+	if sLen >= length || padStr == "" {
 		return s
 	}
-	pad_len := len([]rune(padStr))
-	left_len := 0
-	right_len := 0
-	is_left := true
-	for left_len+s_len+right_len < length {
-		if is_left {
-			left_len += pad_len
-			for left_len+s_len+right_len > length {
-				left_len--
+	return units.fill(padStr, length-sLen) + s
+}
+
+// rightPadUnits pads s on the right, counting characters as units.
+func rightPadUnits(s string, padStr string, length int, units textUnits) string {
+	sLen := units.count(s)
+	if sLen >= length || padStr == "" {
+		return s
+	}
+	return s + units.fill(padStr, length-sLen)
+}
+
+// padUnits pads s on both sides, counting characters as units.
+func padUnits(s string, padStr string, length int, units textUnits) string {
+	sLen := units.count(s)
+	if sLen >= length || padStr == "" {
+		return s
+	}
+	left, right := splitPad(sLen, units.count(padStr), length)
+	return units.fill(padStr, left) + s + units.fill(padStr, right)
+}
+
+// splitPad distributes what is needed to grow sLen to length between the left
+// and right side, padLen at a time starting on the left, as Pad, PadWidth and
+// PadANSI do.
+func splitPad(sLen int, padLen int, length int) (int, int) {
+	left := 0
+	right := 0
+	isLeft := true
+	for left+sLen+right < length {
+		if isLeft {
+			left += padLen
+			for left+sLen+right > length {
+				left--
 			}
 		} else {
-			right_len += pad_len
-			for left_len+s_len+right_len > length {
-				right_len--
+			right += padLen
+			for left+sLen+right > length {
+				right--
 			}
 		}
-		is_left = !is_left
+		isLeft = !isLeft
 	}
-
-	left := LeftPad(s, padStr, left_len+s_len)
-	return RightPad(left, padStr, left_len+s_len+right_len)
+	return left, right
 }
 
 // ExpandLeadingTabs replaces leading tabs in each line of the input string with spaces.
@@ -621,6 +836,8 @@ func Dedupe(s string) string {
 	return string(result)
 }
 
+var pathSeparatorsRegex = regexp.MustCompile(`[\\/]{2,}`)
+
 // ToWindowsPathSeparator converts a given file path to use Windows path separators.
 // It handles empty paths, trims leading and trailing whitespace, and preserves network share paths.
 //
@@ -648,8 +865,7 @@ func ToWindowsPathSeparator(path string) string {
 	}
 
 	// Replace all remaining contiguous separators with single backslash
-	re := regexp.MustCompile(`[\\/]{2,}`)
-	path = strings.ReplaceAll(re.ReplaceAllString(path, "\\"), "/", "\\")
+	path = strings.ReplaceAll(pathSeparatorsRegex.ReplaceAllString(path, "\\"), "/", "\\")
 	if is_unc {
 		path = `\\` + path
 	}
@@ -672,8 +888,7 @@ func ToLinuxPathSeparator(path string) string {
 	}
 
 	// Replace all contiguous separators with single forward slash
-	re := regexp.MustCompile(`[\\/]{2,}`)
-	return strings.ReplaceAll(re.ReplaceAllString(path, "/"), "\\", "/")
+	return strings.ReplaceAll(pathSeparatorsRegex.ReplaceAllString(path, "/"), "\\", "/")
 }
 
 func StartsWith(s, prefix string) bool {
@@ -692,67 +907,39 @@ func Contains(s, substr string) bool {
 // keeps only the lines that match the pattern, and returns them joined
 // with their original line endings preserved.
 func GetRegexMatchedLinesAsString(s string, pattern string) string {
-	if s == "" || pattern == "" {
-		return ""
-	}
-
-	// Detect original line ending style
-	lineEnding := GetLineEnding(s)
-
-	// Compile the regex pattern
-	re, err := regexp.Compile(pattern)
+	p, err := compilePattern(pattern)
 	if err != nil {
 		return ""
 	}
-
-	// Split the s preserving line endings
-	lines := strings.SplitAfter(s, "\n")
-
-	// Filter matching lines
-	var matchedLines []string
-	for _, line := range lines {
-		line = strings.TrimRight(line, "\r\n")
-		if line == "" {
-			continue
-		}
-		if re.MatchString(line) {
-			matchedLines = append(matchedLines, line)
-		}
-	}
-
-	// Join the matched lines
-	return strings.Join(matchedLines, lineEnding)
+	return p.MatchedLines(s)
 }
 
 func GetRegexUnmatchedLinesAsString(s string, pattern string) string {
-	if s == "" || pattern == "" {
+	p, err := compilePattern(pattern)
+	if err != nil {
 		return ""
 	}
+	return p.UnmatchedLines(s)
+}
 
-	// Detect original line ending style
-	lineEnding := GetLineEnding(s)
-
-	// Compile the regex pattern
-	re, err := regexp.Compile(pattern)
+// FilterLines keeps the lines of s selected by pattern and opts and joins them
+// with the line ending style of s. GetRegexMatchedLinesAsString is FilterLines
+// with the zero FilterOptions and GetRegexUnmatchedLinesAsString sets Invert,
+// except that both return "" for an empty pattern.
+// If the pattern is invalid, "" is returned.
+func FilterLines(s string, pattern string, opts FilterOptions) string {
+	result, err := FilterLinesE(s, pattern, opts)
 	if err != nil {
 		return ""
 	}
+	return result
+}
 
-	// Split the s preserving line endings
-	lines := strings.SplitAfter(s, "\n")
-
-	// Filter non-matching lines
-	var unmatchedLines []string
-	for _, line := range lines {
-		line = strings.TrimRight(line, "\r\n")
-		if line == "" {
-			continue
-		}
-		if !re.MatchString(line) {
-			unmatchedLines = append(unmatchedLines, line)
-		}
+// FilterLinesE is like FilterLines but returns a *PatternError if pattern is invalid.
+func FilterLinesE(s string, pattern string, opts FilterOptions) (string, error) {
+	p, err := compilePattern(pattern)
+	if err != nil {
+		return "", err
 	}
-
-	// Join the unmatched lines
-	return strings.Join(unmatchedLines, lineEnding)
+	return p.FilterLines(s, opts), nil
 }
@@ -100,6 +100,69 @@ func TestTrimRight(t *testing.T) {
 	}
 }
 
+func TestTrimSpace(t *testing.T) {
+	tests := []struct {
+		input    string
+		extra    []string
+		expected string
+		left     string
+		right    string
+	}{
+		{"   spaces   ", nil, "spaces", "spaces   ", "   spaces"},
+		{"\u00a0nbsp\u00a0", nil, "nbsp", "nbsp\u00a0", "\u00a0nbsp"},
+		{"\u3000全角\u3000", nil, "全角", "全角\u3000", "\u3000全角"},
+		{"\v\fcontrol\v\f", nil, "control", "control\v\f", "\v\fcontrol"},
+		{"\u2028line\u2029", nil, "line", "line\u2029", "\u2028line"},
+		{"\ufeffbom", nil, "\ufeffbom", "\ufeffbom", "\ufeffbom"},
+		{"\ufeffbom", []string{ZeroWidth}, "bom", "bom", "\ufeffbom"},
+		{"\u200b \u200bzero\u200b", []string{ZeroWidth}, "zero", "zero\u200b", "\u200b \u200bzero"},
+		{"--dashes--", []string{"-"}, "dashes", "dashes--", "--dashes"},
+		{"*_mixed_*", []string{"*", "_"}, "mixed", "mixed_*", "*_mixed"},
+		{"", nil, "", "", ""},
+		{" \u00a0\u3000 ", nil, "", "", ""},
+	}
+
+	for _, test := range tests {
+		if result := TrimSpace(test.input, test.extra...); result != test.expected {
+			t.Errorf("TrimSpace(%q, %q) = %q; want %q",
+				test.input, test.extra, result, test.expected)
+		}
+		if result := TrimLeftSpace(test.input, test.extra...); result != test.left {
+			t.Errorf("TrimLeftSpace(%q, %q) = %q; want %q",
+				test.input, test.extra, result, test.left)
+		}
+		if result := TrimRightSpace(test.input, test.extra...); result != test.right {
+			t.Errorf("TrimRightSpace(%q, %q) = %q; want %q",
+				test.input, test.extra, result, test.right)
+		}
+	}
+}
+
+func TestCollapseWhitespace(t *testing.T) {
+	tests := []struct {
+		input    string
+		extra    []string
+		expected string
+	}{
+		{"", nil, ""},
+		{"hello", nil, "hello"},
+		{"  hello   world  ", nil, "hello world"},
+		{"a \t b\u3000\u3000c\n", nil, "a b c"},
+		{"line one\r\nline two", nil, "line one line two"},
+		{"a\u00a0\u00a0b", nil, "a b"},
+		{"a\u200b\u200bb", nil, "a\u200b\u200bb"},
+		{"a\u200b \u200bb", []string{ZeroWidth}, "a b"},
+		{"👨\u200d👩\u200d👧  family", []string{ZeroWidth}, "👨\u200d👩\u200d👧 family"},
+	}
+
+	for _, test := range tests {
+		if result := CollapseWhitespace(test.input, test.extra...); result != test.expected {
+			t.Errorf("CollapseWhitespace(%q, %q) = %q; want %q",
+				test.input, test.extra, result, test.expected)
+		}
+	}
+}
+
 func TestGetIndentString(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -234,6 +297,112 @@ func TestSucc(t *testing.T) {
 	}
 }
 
+func TestPred(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"1", "0"},
+		{"b", "a"},
+		{"B", "A"},
+		{"01", "00"},
+
+		// Basic alphanumeric cases
+		{"THX1139", "THX1138"},
+		{"<<koalb>>", "<<koala>>"},
+		{"**+", "***"},
+
+		// Borrowing
+		{"b0", "a9"},
+		{"ba", "az"},
+		{"BA", "AZ"},
+		{"20", "19"},
+		{"a9a0", "a8z9"},
+
+		// Shrinking past a prefix added by Succ
+		{"10", "9"},
+		{"100", "99"},
+		{"aa", "z"},
+		{"AA", "Z"},
+		{"aaa", "zz"},
+		{"AAAA0000", "ZZZ9999"},
+		{"aaa00aa00", "zz99zz99"},
+		{"100aa00aa", "99zz99zz"},
+
+		// Smallest values of their form
+		{"a", "`"},
+		{"A", "@"},
+		{"0", "/"},
+		{"a0", "`9"},
+		{"A0", "@9"},
+
+		// Non-alphanumeric characters stop the borrow
+		{"a.0", "a-9"},
+	}
+
+	for _, test := range tests {
+		result := Pred(test.input)
+		if result != test.expected {
+			t.Errorf("Pred(%q) = %q; want %q",
+				test.input, result, test.expected)
+		}
+		if test.input != "" && Succ(result) != test.input {
+			t.Errorf("Succ(Pred(%q)) = %q; want %q",
+				test.input, Succ(result), test.input)
+		}
+	}
+}
+
+func TestSuccRange(t *testing.T) {
+	tests := []struct {
+		from     string
+		to       string
+		expected []string
+	}{
+		{"a", "e", []string{"a", "b", "c", "d", "e"}},
+		{"a8", "b1", []string{"a8", "a9", "b0", "b1"}},
+		{"8", "11", []string{"8", "9", "10", "11"}},
+		{"y", "ab", []string{"y", "z", "aa", "ab"}},
+		{"a", "a", []string{"a"}},
+		{"e", "a", nil},
+		{"aa", "z", nil},
+		{"a", "B", nil},
+		{"A", "a", []string{"A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M",
+			"N", "O", "P", "Q", "R", "S", "T", "U", "V", "W", "X", "Y", "Z"}},
+		{"", "a", []string{""}},
+	}
+
+	for _, test := range tests {
+		result := SuccRange(test.from, test.to)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("SuccRange(%q, %q) = %q; want %q",
+				test.from, test.to, result, test.expected)
+		}
+	}
+}
+
+func TestSuccSeq(t *testing.T) {
+	var got []string
+	for v := range SuccSeq("a", "zz", 3) {
+		got = append(got, v)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SuccSeq with limit 3 = %q; want %q", got, want)
+	}
+
+	got = nil
+	for v := range SuccSeq("1", "100", 0) {
+		got = append(got, v)
+		if v == "12" {
+			break
+		}
+	}
+	if want := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SuccSeq with break = %q; want %q", got, want)
+	}
+}
+
 func TestHighlight(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -1174,6 +1343,94 @@ func TestGrepGroup(t *testing.T) {
 	}
 }
 
+func TestGrepGroups(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		pattern  string
+		expected []map[string]string
+	}{
+		{
+			name:    "named and numbered groups",
+			s:       "name: John, age: 30, name: Jane, age: 25",
+			pattern: `name: (?P<name>\w+), age: (\d+)`,
+			expected: []map[string]string{
+				{"0": "name: John, age: 30", "1": "John", "name": "John", "2": "30"},
+				{"0": "name: Jane, age: 25", "1": "Jane", "name": "Jane", "2": "25"},
+			},
+		},
+		{
+			name:    "optional group did not participate",
+			s:       "a=1 b",
+			pattern: `(?P<key>\w)(?:=(?P<value>\d))?`,
+			expected: []map[string]string{
+				{"0": "a=1", "1": "a", "key": "a", "2": "1", "value": "1"},
+				{"0": "b", "1": "b", "key": "b"},
+			},
+		},
+		{
+			name:    "group matched empty string",
+			s:       "a= b=2",
+			pattern: `(?P<key>\w)=(?P<value>\d*)`,
+			expected: []map[string]string{
+				{"0": "a=", "1": "a", "key": "a", "2": "", "value": ""},
+				{"0": "b=2", "1": "b", "key": "b", "2": "2", "value": "2"},
+			},
+		},
+		{
+			name:     "no groups",
+			s:        "hello world",
+			pattern:  `o`,
+			expected: []map[string]string{{"0": "o"}, {"0": "o"}},
+		},
+		{
+			name:     "no match",
+			s:        "hello world",
+			pattern:  `\d`,
+			expected: []map[string]string{},
+		},
+		{
+			name:     "invalid regex",
+			s:        "hello world",
+			pattern:  "[",
+			expected: []map[string]string{},
+		},
+		{
+			name:     "unicode",
+			s:        "你好=世界",
+			pattern:  `(?P<k>\p{Han}+)=(?P<v>\p{Han}+)`,
+			expected: []map[string]string{{"0": "你好=世界", "1": "你好", "k": "你好", "2": "世界", "v": "世界"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GrepGroups(tt.s, tt.pattern)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("GrepGroups(%q, %q) = %v; want %v", tt.s, tt.pattern, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMatchGroups(t *testing.T) {
+	result := MatchGroups("a=1 b=2", `(?P<key>\w)=(?P<value>\d)(x)?`)
+	expected := map[string]string{"0": "a=1", "1": "a", "key": "a", "2": "1", "value": "1"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("MatchGroups = %v; want %v", result, expected)
+	}
+	if _, ok := result["3"]; ok {
+		t.Error("MatchGroups contains a group that did not participate")
+	}
+
+	if result := MatchGroups("abc", `\d`); result != nil {
+		t.Errorf("MatchGroups with no match = %v; want nil", result)
+	}
+	if result := MatchGroups("abc", `(`); result != nil {
+		t.Errorf("MatchGroups with invalid pattern = %v; want nil", result)
+	}
+}
+
 func TestExpandLeadingTabs(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -1467,3 +1724,111 @@ func TestRegexLineMatching(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		pattern string
+		opts    FilterOptions
+		want    string
+	}{
+		{
+			name:    "empty lines dropped by default",
+			input:   "a\n\n\nb\n",
+			pattern: "^$",
+			opts:    FilterOptions{},
+			want:    "",
+		},
+		{
+			name:    "keep empty lines",
+			input:   "a\n\n\nb\n",
+			pattern: "^$",
+			opts:    FilterOptions{KeepEmptyLines: true},
+			want:    "\n",
+		},
+		{
+			name:    "trailing line ending is not an empty line",
+			input:   "a\n",
+			pattern: "^$",
+			opts:    FilterOptions{KeepEmptyLines: true, KeepTrailingNewline: true},
+			want:    "",
+		},
+		{
+			name:    "inverted keeps paragraph breaks",
+			input:   "# comment\npara one\n\npara two\n# comment",
+			pattern: "^#",
+			opts:    FilterOptions{KeepEmptyLines: true, Invert: true},
+			want:    "para one\n\npara two",
+		},
+		{
+			name:    "inverted drops empty lines by default",
+			input:   "# comment\npara one\n\npara two",
+			pattern: "^#",
+			opts:    FilterOptions{Invert: true},
+			want:    "para one\npara two",
+		},
+		{
+			name:    "raw line matching",
+			input:   "  indented\nflush",
+			pattern: `^\w`,
+			opts:    FilterOptions{},
+			want:    "flush",
+		},
+		{
+			name:    "trimmed line matching",
+			input:   "  indented\nflush\n   \n",
+			pattern: `^\w`,
+			opts:    FilterOptions{MatchTrimmed: true},
+			want:    "  indented\nflush",
+		},
+		{
+			name:    "whitespace-only lines are empty when trimmed",
+			input:   "a\n  \nb",
+			pattern: "^$",
+			opts:    FilterOptions{MatchTrimmed: true, KeepEmptyLines: true},
+			want:    "  ",
+		},
+		{
+			name:    "keep trailing newline",
+			input:   "apple\r\nbanana\r\ncherry\r\n",
+			pattern: "an",
+			opts:    FilterOptions{KeepTrailingNewline: true},
+			want:    "banana\r\n",
+		},
+		{
+			name:    "no trailing newline to keep",
+			input:   "apple\nbanana",
+			pattern: "an",
+			opts:    FilterOptions{KeepTrailingNewline: true},
+			want:    "banana",
+		},
+		{
+			name:    "empty pattern matches every line",
+			input:   "a\nb",
+			pattern: "",
+			opts:    FilterOptions{},
+			want:    "a\nb",
+		},
+		{
+			name:    "invalid pattern",
+			input:   "a\nb",
+			pattern: "(",
+			opts:    FilterOptions{},
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FilterLines(tt.input, tt.pattern, tt.opts)
+			if got != tt.want {
+				t.Errorf("FilterLines(%q, %q, %+v) = %q, want %q", tt.input, tt.pattern, tt.opts, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := FilterLinesE("a", "(", FilterOptions{}); err == nil {
+		t.Error("FilterLinesE with invalid pattern returned no error")
+	}
+}
@@ -0,0 +1,147 @@
+package s
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Position is a location in a string.
+type Position struct {
+	// Byte is the offset in bytes from the start of the string.
+	Byte int
+	// Rune is the offset in runes from the start of the string.
+	Rune int
+	// Line is the 1-based line number, where lines end with "\n".
+	Line int
+	// Column is the 1-based column within the line, counted in runes.
+	Column int
+}
+
+// Span is a part of a string matched by a pattern or one of its groups. End is
+// the position just after the last character of the span.
+type Span struct {
+	Text       string
+	Start, End Position
+	// Matched is false for a group that did not take part in the match, in
+	// which case Text is empty and the positions are zero. It distinguishes such
+	// groups from groups that matched the empty string.
+	Matched bool
+}
+
+// Match describes one match of a pattern: the span of the whole match and the
+// spans of its groups.
+type Match struct {
+	Span
+	// Groups holds the span of every group by index; Groups[0] is the whole match.
+	Groups []Span
+	// Named holds the span of every named group by name.
+	Named map[string]Span
+}
+
+// FindSpans returns every match of the pattern in s along with the positions of
+// the match and its groups, so that callers can render or process matches
+// themselves. It returns an empty slice if there is no match.
+//
+// Example:
+//
+//	p := MustCompile(`(?P<key>\w+)=`)
+//	m := p.FindSpans("a\nkey=1")[0]
+//	// m.Text is "key=", m.Start is {Byte: 2, Rune: 2, Line: 2, Column: 1}
+//	// and m.Named["key"].End is {Byte: 5, Rune: 5, Line: 2, Column: 4}
+func (p *Pattern) FindSpans(s string) []Match {
+	return p.buildMatches(s, p.re.FindAllStringSubmatchIndex(s, -1))
+}
+
+// buildMatches turns the submatch locations of the pattern in s, as returned by
+// FindAllStringSubmatchIndex, into matches.
+func (p *Pattern) buildMatches(s string, locations [][]int) []Match {
+	if locations == nil {
+		return []Match{}
+	}
+
+	// Resolve all offsets in a single pass over s
+	var offsets []int
+	for _, loc := range locations {
+		for _, offset := range loc {
+			if offset >= 0 {
+				offsets = append(offsets, offset)
+			}
+		}
+	}
+	positions := positionsOf(s, offsets)
+
+	names := p.re.SubexpNames()
+	matches := make([]Match, 0, len(locations))
+	for _, loc := range locations {
+		var match Match
+		match.Groups = make([]Span, len(loc)/2)
+		for i := range match.Groups {
+			start, end := loc[2*i], loc[2*i+1]
+			if start < 0 {
+				continue
+			}
+			match.Groups[i] = Span{
+				Text:    s[start:end],
+				Start:   positions[start],
+				End:     positions[end],
+				Matched: true,
+			}
+		}
+		match.Span = match.Groups[0]
+
+		match.Named = make(map[string]Span)
+		for i, name := range names {
+			if name != "" {
+				match.Named[name] = match.Groups[i]
+			}
+		}
+
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+// positionsOf returns the positions in s of the given byte offsets.
+func positionsOf(s string, offsets []int) map[int]Position {
+	offsets = slices.Clone(offsets)
+	slices.Sort(offsets)
+	offsets = slices.Compact(offsets)
+
+	positions := make(map[int]Position, len(offsets))
+	current := Position{Line: 1, Column: 1}
+	for _, offset := range offsets {
+		segment := s[current.Byte:offset]
+		current.Rune += utf8.RuneCountInString(segment)
+		if i := strings.LastIndexByte(segment, '\n'); i >= 0 {
+			current.Line += strings.Count(segment, "\n")
+			current.Column = utf8.RuneCountInString(segment[i+1:]) + 1
+		} else {
+			current.Column += utf8.RuneCountInString(segment)
+		}
+		current.Byte = offset
+		positions[offset] = current
+	}
+	return positions
+}
+
+// FindSpans returns every match of pattern in s along with the positions of the
+// match and its groups. See Pattern.FindSpans. If the pattern is invalid, an
+// empty slice is returned.
+func FindSpans(s string, pattern string) []Match {
+	result, err := FindSpansE(s, pattern)
+	if err != nil {
+		return []Match{}
+	}
+	return result
+}
+
+// FindSpansE is like FindSpans but returns a *PatternError if the pattern does
+// not compile.
+func FindSpansE(s string, pattern string) ([]Match, error) {
+	p, err := compilePattern(pattern)
+	if err != nil {
+		return []Match{}, err
+	}
+	return p.FindSpans(s), nil
+}
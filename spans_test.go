@@ -0,0 +1,120 @@
+package s
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindSpans(t *testing.T) {
+	input := "héllo wörld\r\nsay 你好=42\n"
+	matches := FindSpans(input, `(?P<key>\p{L}+)=(?P<value>\d+)(x)?`)
+	if len(matches) != 1 {
+		t.Fatalf("FindSpans returned %d matches; want 1", len(matches))
+	}
+
+	m := matches[0]
+	want := Span{
+		Text:    "你好=42",
+		Start:   Position{Byte: 19, Rune: 17, Line: 2, Column: 5},
+		End:     Position{Byte: 28, Rune: 22, Line: 2, Column: 10},
+		Matched: true,
+	}
+	if m.Span != want {
+		t.Errorf("match span = %+v; want %+v", m.Span, want)
+	}
+	if !reflect.DeepEqual(m.Groups[0], want) {
+		t.Errorf("Groups[0] = %+v; want %+v", m.Groups[0], want)
+	}
+
+	wantKey := Span{
+		Text:    "你好",
+		Start:   Position{Byte: 19, Rune: 17, Line: 2, Column: 5},
+		End:     Position{Byte: 25, Rune: 19, Line: 2, Column: 7},
+		Matched: true,
+	}
+	if m.Groups[1] != wantKey || m.Named["key"] != wantKey {
+		t.Errorf("key group = %+v / %+v; want %+v", m.Groups[1], m.Named["key"], wantKey)
+	}
+
+	wantValue := Span{
+		Text:    "42",
+		Start:   Position{Byte: 26, Rune: 20, Line: 2, Column: 8},
+		End:     Position{Byte: 28, Rune: 22, Line: 2, Column: 10},
+		Matched: true,
+	}
+	if m.Named["value"] != wantValue {
+		t.Errorf("value group = %+v; want %+v", m.Named["value"], wantValue)
+	}
+
+	if len(m.Groups) != 4 || m.Groups[3] != (Span{}) {
+		t.Errorf("unmatched group = %+v; want zero Span", m.Groups[3])
+	}
+	if len(m.Named) != 2 {
+		t.Errorf("Named has %d entries; want 2", len(m.Named))
+	}
+}
+
+func TestFindSpansPositions(t *testing.T) {
+	tests := []struct {
+		input   string
+		pattern string
+		starts  []Position
+		ends    []Position
+	}{
+		{
+			input:   "ab\nab\n\nab",
+			pattern: `b`,
+			starts:  []Position{{1, 1, 1, 2}, {4, 4, 2, 2}, {8, 8, 4, 2}},
+			ends:    []Position{{2, 2, 1, 3}, {5, 5, 2, 3}, {9, 9, 4, 3}},
+		},
+		{
+			input:   "x\ny",
+			pattern: `x\n`,
+			starts:  []Position{{0, 0, 1, 1}},
+			ends:    []Position{{2, 2, 2, 1}},
+		},
+		{
+			input:   "ñ",
+			pattern: `$`,
+			starts:  []Position{{2, 1, 1, 2}},
+			ends:    []Position{{2, 1, 1, 2}},
+		},
+	}
+
+	for _, test := range tests {
+		matches := FindSpans(test.input, test.pattern)
+		if len(matches) != len(test.starts) {
+			t.Errorf("FindSpans(%q, %q) returned %d matches; want %d",
+				test.input, test.pattern, len(matches), len(test.starts))
+			continue
+		}
+		for i, m := range matches {
+			if m.Start != test.starts[i] || m.End != test.ends[i] {
+				t.Errorf("FindSpans(%q, %q)[%d] = %+v..%+v; want %+v..%+v",
+					test.input, test.pattern, i, m.Start, m.End, test.starts[i], test.ends[i])
+			}
+		}
+	}
+}
+
+func TestFindSpansEmptyGroup(t *testing.T) {
+	m := FindSpans("a=", `(\w)=(\d*)`)[0]
+	if !m.Groups[2].Matched || m.Groups[2].Text != "" {
+		t.Errorf("empty group = %+v; want a matched empty span", m.Groups[2])
+	}
+	if m.Groups[2].Start != (Position{Byte: 2, Rune: 2, Line: 1, Column: 3}) {
+		t.Errorf("empty group starts at %+v", m.Groups[2].Start)
+	}
+}
+
+func TestFindSpansNoMatch(t *testing.T) {
+	if matches := FindSpans("abc", `\d`); matches == nil || len(matches) != 0 {
+		t.Errorf("FindSpans with no match = %#v; want empty slice", matches)
+	}
+	if matches := FindSpans("abc", `(`); matches == nil || len(matches) != 0 {
+		t.Errorf("FindSpans with invalid pattern = %#v; want empty slice", matches)
+	}
+	if _, err := FindSpansE("abc", `(`); err == nil {
+		t.Error("FindSpansE with invalid pattern returned no error")
+	}
+}
@@ -0,0 +1,115 @@
+package s
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+)
+
+// streamBufferSize is the read buffer size used by the streaming line filters.
+// Longer lines are still handled, they are just assembled from several reads.
+const streamBufferSize = 64 * 1024
+
+// LineCounts reports how many lines a streaming line filter read and wrote.
+type LineCounts struct {
+	Read    int
+	Written int
+}
+
+// CopyMatchedLines is the streaming equivalent of MatchedLines. It reads lines
+// from r and writes the non-empty ones that match the pattern to w, each with
+// its original line ending. Lines of any length are supported. It returns the
+// line counts and the first error encountered while reading or writing, if any;
+// reaching the end of r is not an error. A pattern compiled from "" writes nothing.
+func (p *Pattern) CopyMatchedLines(w io.Writer, r io.Reader) (LineCounts, error) {
+	return p.copyLines(w, r, true)
+}
+
+// CopyUnmatchedLines is the streaming equivalent of UnmatchedLines. It writes the
+// non-empty lines read from r that do not match the pattern to w, like
+// CopyMatchedLines.
+func (p *Pattern) CopyUnmatchedLines(w io.Writer, r io.Reader) (LineCounts, error) {
+	return p.copyLines(w, r, false)
+}
+
+func (p *Pattern) copyLines(w io.Writer, r io.Reader, keepMatched bool) (LineCounts, error) {
+	var counts LineCounts
+	if p.re.String() == "" {
+		return counts, nil
+	}
+
+	err := eachReaderLine(r, func(line []byte) error {
+		counts.Read++
+
+		if !p.keepsLineBytes(bytes.TrimRight(line, "\r\n"), FilterOptions{Invert: !keepMatched}) {
+			return nil
+		}
+
+		if _, err := w.Write(line); err != nil {
+			return err
+		}
+		counts.Written++
+		return nil
+	})
+	return counts, err
+}
+
+// eachReaderLine calls callback with every line read from r, including its line
+// ending. The slice passed to callback is only valid until it returns. Reading
+// stops at the end of r or at the first error, from r or callback, which is
+// returned.
+func eachReaderLine(r io.Reader, callback func(line []byte) error) error {
+	reader := bufio.NewReaderSize(r, streamBufferSize)
+	// long accumulates lines that don't fit in the reader's buffer
+	var long []byte
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if errors.Is(err, bufio.ErrBufferFull) {
+			long = append(long, chunk...)
+			continue
+		}
+
+		line := chunk
+		if len(long) > 0 {
+			long = append(long, chunk...)
+			line = long
+		}
+		if len(line) > 0 {
+			if cbErr := callback(line); cbErr != nil {
+				return cbErr
+			}
+		}
+		long = long[:0]
+
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// CopyRegexMatchedLines reads lines from r and writes those matching pattern to w,
+// each with its original line ending, like GetRegexMatchedLinesAsString does for
+// strings. If the pattern does not compile, a *PatternError is returned and
+// nothing is read.
+func CopyRegexMatchedLines(w io.Writer, r io.Reader, pattern string) (LineCounts, error) {
+	p, err := compilePattern(pattern)
+	if err != nil {
+		return LineCounts{}, err
+	}
+	return p.CopyMatchedLines(w, r)
+}
+
+// CopyRegexUnmatchedLines reads lines from r and writes those not matching pattern
+// to w, each with its original line ending, like GetRegexUnmatchedLinesAsString
+// does for strings.
+func CopyRegexUnmatchedLines(w io.Writer, r io.Reader, pattern string) (LineCounts, error) {
+	p, err := compilePattern(pattern)
+	if err != nil {
+		return LineCounts{}, err
+	}
+	return p.CopyUnmatchedLines(w, r)
+}
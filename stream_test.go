@@ -0,0 +1,177 @@
+package s
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestCopyRegexLines(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		pattern   string
+		matched   string
+		unmatched string
+		counts    LineCounts
+	}{
+		{
+			name:      "lf",
+			input:     "apple\nbanana\ncherry\n",
+			pattern:   `an`,
+			matched:   "banana\n",
+			unmatched: "apple\ncherry\n",
+			counts:    LineCounts{Read: 3, Written: 1},
+		},
+		{
+			name:      "mixed endings are preserved",
+			input:     "error one\r\ninfo\nerror two\nerror three",
+			pattern:   `^error`,
+			matched:   "error one\r\nerror two\nerror three",
+			unmatched: "info\n",
+			counts:    LineCounts{Read: 4, Written: 3},
+		},
+		{
+			name:      "empty lines are skipped",
+			input:     "a\n\r\n\nb\n",
+			pattern:   `^$`,
+			matched:   "",
+			unmatched: "a\nb\n",
+			counts:    LineCounts{Read: 4, Written: 0},
+		},
+		{
+			name:      "empty input",
+			input:     "",
+			pattern:   `.`,
+			matched:   "",
+			unmatched: "",
+			counts:    LineCounts{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			counts, err := CopyRegexMatchedLines(&out, strings.NewReader(tt.input), tt.pattern)
+			if err != nil {
+				t.Fatalf("CopyRegexMatchedLines error: %v", err)
+			}
+			if out.String() != tt.matched {
+				t.Errorf("CopyRegexMatchedLines wrote %q; want %q", out.String(), tt.matched)
+			}
+			if counts.Read != tt.counts.Read || counts.Written != tt.counts.Written {
+				t.Errorf("CopyRegexMatchedLines counts = %+v; want %+v", counts, tt.counts)
+			}
+
+			out.Reset()
+			counts, err = CopyRegexUnmatchedLines(&out, iotest.OneByteReader(strings.NewReader(tt.input)), tt.pattern)
+			if err != nil {
+				t.Fatalf("CopyRegexUnmatchedLines error: %v", err)
+			}
+			if out.String() != tt.unmatched {
+				t.Errorf("CopyRegexUnmatchedLines wrote %q; want %q", out.String(), tt.unmatched)
+			}
+			if counts.Read != tt.counts.Read {
+				t.Errorf("CopyRegexUnmatchedLines read %d lines; want %d", counts.Read, tt.counts.Read)
+			}
+		})
+	}
+}
+
+func TestCopyMatchedLinesAgreesWithMatchedLines(t *testing.T) {
+	input := "user=alice\r\n\r\nuser=bob\r\nid=3\r\n"
+	p := MustCompile(`user=`)
+
+	var out bytes.Buffer
+	if _, err := p.CopyMatchedLines(&out, strings.NewReader(input)); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := TrimRight(out.String()), p.MatchedLines(input); got != want {
+		t.Errorf("CopyMatchedLines wrote %q; MatchedLines returned %q", got, want)
+	}
+}
+
+func TestCopyMatchedLinesLongLines(t *testing.T) {
+	long := strings.Repeat("x", 3*streamBufferSize+17)
+	input := "short\n" + long + "MATCH\r\n" + long + "\nMATCH" + long
+
+	var out bytes.Buffer
+	counts, err := CopyRegexMatchedLines(&out, strings.NewReader(input), `MATCH`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := long + "MATCH\r\n" + "MATCH" + long
+	if out.String() != want {
+		t.Errorf("CopyRegexMatchedLines wrote %d bytes; want %d", out.Len(), len(want))
+	}
+	if counts != (LineCounts{Read: 4, Written: 2}) {
+		t.Errorf("counts = %+v; want {Read:4 Written:2}", counts)
+	}
+}
+
+func TestCopyMatchedLinesAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are unreliable under the race detector")
+	}
+	p := MustCompile(`ERROR`)
+	allocs := func(lines int) float64 {
+		input := strings.Repeat("2024-12-27 ERROR connection refused\r\nINFO ok\n", lines)
+		return testing.AllocsPerRun(10, func() {
+			if _, err := p.CopyMatchedLines(io.Discard, strings.NewReader(input)); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+
+	// Allocations must not grow with the number of lines
+	if few, many := allocs(10), allocs(10000); many > few {
+		t.Errorf("CopyMatchedLines allocated %v times for 10 lines and %v times for 10000", few, many)
+	}
+}
+
+type failingWriter struct {
+	err error
+}
+
+func (w failingWriter) Write(p []byte) (int, error) {
+	return 0, w.err
+}
+
+func TestCopyMatchedLinesErrors(t *testing.T) {
+	errRead := errors.New("read failed")
+	errWrite := errors.New("write failed")
+
+	var out bytes.Buffer
+	counts, err := CopyRegexMatchedLines(&out, iotest.DataErrReader(strings.NewReader("a\nb\n")), `.`)
+	if err != nil || out.String() != "a\nb\n" || counts.Written != 2 {
+		t.Errorf("DataErrReader: got %q, %+v, %v", out.String(), counts, err)
+	}
+
+	out.Reset()
+	_, err = CopyRegexMatchedLines(&out, iotest.TimeoutReader(strings.NewReader("a\nb\n")), `.`)
+	if !errors.Is(err, iotest.ErrTimeout) {
+		t.Errorf("TimeoutReader: error = %v; want %v", err, iotest.ErrTimeout)
+	}
+
+	_, err = CopyRegexMatchedLines(&out, iotest.ErrReader(errRead), `.`)
+	if !errors.Is(err, errRead) {
+		t.Errorf("ErrReader: error = %v; want %v", err, errRead)
+	}
+
+	counts, err = CopyRegexMatchedLines(failingWriter{errWrite}, strings.NewReader("a\nb\n"), `.`)
+	if !errors.Is(err, errWrite) {
+		t.Errorf("failing writer: error = %v; want %v", err, errWrite)
+	}
+	if counts != (LineCounts{Read: 1, Written: 0}) {
+		t.Errorf("failing writer: counts = %+v; want {Read:1 Written:0}", counts)
+	}
+
+	_, err = CopyRegexMatchedLines(&out, strings.NewReader("a"), `(`)
+	var patternErr *PatternError
+	if !errors.As(err, &patternErr) {
+		t.Errorf("invalid pattern: error = %v; want *PatternError", err)
+	}
+}
@@ -0,0 +1,163 @@
+package s
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// East_Asian_Width values that occupy two terminal cells.
+const (
+	eaWide uint8 = iota + 1
+	eaFullwidth
+)
+
+// emojiPresentation marks code points with Emoji_Presentation=Yes.
+const emojiPresentation uint8 = 1
+
+// runeWidth returns the number of terminal cells occupied by r on its own.
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20 || (r >= 0x7F && r < 0xA0):
+		// C0 and C1 controls
+		return 0
+	case r < 0x7F:
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		// Combining marks and format characters such as ZWJ
+		return 0
+	case (r >= 0x1160 && r <= 0x11FF) || (r >= 0xD7B0 && r <= 0xD7FF):
+		// Hangul medial vowels and final consonants combine with the leading jamo
+		return 0
+	case lookupProperty(eastAsianWidthRanges, r) != 0:
+		return 2
+	case lookupProperty(emojiPresentationRanges, r) != 0:
+		return 2
+	case r >= 0x20000 && r <= 0x3FFFD:
+		// Unlisted code points in planes 2 and 3 default to Wide
+		return 2
+	}
+	return 1
+}
+
+// graphemeWidth returns the number of terminal cells occupied by a single
+// grapheme cluster.
+func graphemeWidth(g string) int {
+	r, size := utf8.DecodeRuneInString(g)
+	width := runeWidth(r)
+
+	// Regional indicator pairs render as one wide flag
+	if lookupProperty(graphemeBreakRanges, r) == gbRegionalIndicator {
+		return 2
+	}
+
+	if width == 0 {
+		// A cluster led by a zero-width rune takes the width of what follows
+		for _, r := range g[size:] {
+			if w := runeWidth(r); w > width {
+				width = w
+			}
+		}
+		return width
+	}
+
+	// VARIATION SELECTOR-16 requests emoji presentation
+	if width == 1 && strings.ContainsRune(g[size:], '\uFE0F') {
+		return 2
+	}
+
+	return width
+}
+
+// DisplayWidth returns the number of terminal cells needed to display s. CJK and
+// emoji characters take two cells, combining marks and control characters none,
+// and every other grapheme cluster one.
+func DisplayWidth(s string) int {
+	width := 0
+	for len(s) > 0 {
+		n := nextGrapheme(s)
+		width += graphemeWidth(s[:n])
+		s = s[n:]
+	}
+	return width
+}
+
+// takeWidth returns the longest prefix of s, cut at grapheme cluster boundaries,
+// that fits in width cells, along with its display width.
+func takeWidth(s string, width int) (string, int) {
+	taken := 0
+	pos := 0
+	for pos < len(s) {
+		n := nextGrapheme(s[pos:])
+		w := graphemeWidth(s[pos : pos+n])
+		if taken+w > width {
+			break
+		}
+		taken += w
+		pos += n
+	}
+	return s[:pos], taken
+}
+
+// fillWidth repeats padStr to fill exactly width cells. When a wide character of
+// padStr would overflow the last cell, the remainder is filled with spaces.
+func fillWidth(padStr string, width int) string {
+	padWidth := DisplayWidth(padStr)
+	if padWidth == 0 || width <= 0 {
+		return ""
+	}
+	fill, taken := takeWidth(Repeat(padStr, width/padWidth+1), width)
+	return fill + strings.Repeat(" ", width-taken)
+}
+
+// LeftPadWidth is like LeftPad but measures s, padStr and width in terminal cells
+// (see DisplayWidth), so the result lines up in a terminal.
+func LeftPadWidth(s string, padStr string, width int) string {
+	sWidth := DisplayWidth(s)
+	if sWidth >= width || DisplayWidth(padStr) == 0 {
+		return s
+	}
+	return fillWidth(padStr, width-sWidth) + s
+}
+
+// RightPadWidth is like RightPad but measures s, padStr and width in terminal cells.
+func RightPadWidth(s string, padStr string, width int) string {
+	sWidth := DisplayWidth(s)
+	if sWidth >= width || DisplayWidth(padStr) == 0 {
+		return s
+	}
+	return s + fillWidth(padStr, width-sWidth)
+}
+
+// PadWidth is like Pad but measures s, padStr and width in terminal cells.
+func PadWidth(s string, padStr string, width int) string {
+	sWidth := DisplayWidth(s)
+	padWidth := DisplayWidth(padStr)
+	if sWidth >= width || padWidth == 0 {
+		return s
+	}
+	left, right := splitPad(sWidth, padWidth, width)
+	return fillWidth(padStr, left) + s + fillWidth(padStr, right)
+}
+
+// TruncateWidth shortens s to at most width terminal cells. If s has to be cut, it
+// is cut at a grapheme cluster boundary and ellipsis is appended, with the
+// ellipsis counted towards width.
+//
+// Example:
+//
+//	TruncateWidth("你好世界", 5, "…") // Returns "你好…"
+func TruncateWidth(s string, width int, ellipsis string) string {
+	if DisplayWidth(s) <= width {
+		return s
+	}
+
+	ellipsisWidth := DisplayWidth(ellipsis)
+	if ellipsisWidth > width {
+		truncated, _ := takeWidth(ellipsis, width)
+		return truncated
+	}
+
+	truncated, _ := takeWidth(s, width-ellipsisWidth)
+	return truncated + ellipsis
+}
@@ -0,0 +1,109 @@
+package s
+
+import "testing"
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{"", 0},
+		{"hello", 5},
+		{"你好世界", 8},
+		{"こんにちは", 10},
+		{"ｈｅｌｌｏ", 10},
+		{"ﾊﾛｰ", 3},
+		{"cafe\u0301", 4},
+		{"\u0301", 0},
+		{"a\tb", 2},
+		{"\x1b", 0},
+		{"😀", 2},
+		{"👍🏽", 2},
+		{"👨\u200D👩\u200D👧", 2},
+		{"🇯🇵", 2},
+		{"❤", 1},
+		{"❤\uFE0F", 2},
+		{"1\uFE0F\u20E3", 2},
+		{"한국어", 6},
+		{"각", 2},
+		{"a\u200Db", 2},
+		{"…", 1},
+	}
+
+	for _, test := range tests {
+		result := DisplayWidth(test.input)
+		if result != test.expected {
+			t.Errorf("DisplayWidth(%q) = %d; want %d", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestPadWidth(t *testing.T) {
+	tests := []struct {
+		name     string
+		fn       func(string, string, int) string
+		s        string
+		padStr   string
+		width    int
+		expected string
+	}{
+		{"LeftPadWidth_ASCII", LeftPadWidth, "abc", " ", 5, "  abc"},
+		{"LeftPadWidth_CJK", LeftPadWidth, "你好", " ", 6, "  你好"},
+		{"LeftPadWidth_NoChange", LeftPadWidth, "你好", " ", 4, "你好"},
+		{"LeftPadWidth_WidePad", LeftPadWidth, "ab", "世", 5, "世 ab"},
+		{"LeftPadWidth_EmptyPad", LeftPadWidth, "ab", "", 5, "ab"},
+		{"LeftPadWidth_ZeroWidthPad", LeftPadWidth, "ab", "\u0301", 5, "ab"},
+		{"RightPadWidth_ASCII", RightPadWidth, "abc", ".", 5, "abc.."},
+		{"RightPadWidth_CJK", RightPadWidth, "你好", " ", 6, "你好  "},
+		{"RightPadWidth_Emoji", RightPadWidth, "😀", "-", 4, "😀--"},
+		{"RightPadWidth_Combining", RightPadWidth, "cafe\u0301", " ", 6, "cafe\u0301  "},
+		{"RightPadWidth_MultiChar", RightPadWidth, "ab", "XY", 7, "abXYXYX"},
+		{"PadWidth_ASCII", PadWidth, "abc", " ", 7, "  abc  "},
+		{"PadWidth_CJK", PadWidth, "你好", " ", 8, "  你好  "},
+		{"PadWidth_Odd", PadWidth, "你好", " ", 7, "  你好 "},
+		{"PadWidth_WidePad", PadWidth, "ab", "世", 7, "世 ab世"},
+		{"PadWidth_NoChange", PadWidth, "你好", " ", 3, "你好"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.fn(tt.s, tt.padStr, tt.width)
+			if got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+			if DisplayWidth(tt.s) < tt.width && DisplayWidth(tt.padStr) > 0 && DisplayWidth(got) != tt.width {
+				t.Errorf("DisplayWidth(%q) = %d; want %d", got, DisplayWidth(got), tt.width)
+			}
+		})
+	}
+}
+
+func TestTruncateWidth(t *testing.T) {
+	tests := []struct {
+		s        string
+		width    int
+		ellipsis string
+		expected string
+	}{
+		{"hello world", 20, "...", "hello world"},
+		{"hello world", 11, "...", "hello world"},
+		{"hello world", 8, "...", "hello..."},
+		{"hello world", 8, "", "hello wo"},
+		{"你好世界", 5, "…", "你好…"},
+		{"你好世界", 6, "…", "你好…"},
+		{"你好世界", 4, "", "你好"},
+		{"你好世界", 3, "", "你"},
+		{"cafe\u0301s", 4, "", "cafe\u0301"},
+		{"👨\u200D👩\u200D👧👨\u200D👩\u200D👧", 3, "", "👨\u200D👩\u200D👧"},
+		{"hello", 2, "...", ".."},
+		{"hello", 0, "...", ""},
+	}
+
+	for _, test := range tests {
+		result := TruncateWidth(test.s, test.width, test.ellipsis)
+		if result != test.expected {
+			t.Errorf("TruncateWidth(%q, %d, %q) = %q; want %q",
+				test.s, test.width, test.ellipsis, result, test.expected)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package s
+
+import (
+	"strings"
+)
+
+// WidthMeasure selects how the width of text is measured.
+type WidthMeasure int
+
+const (
+	// MeasureRunes counts runes, like LenRune.
+	MeasureRunes WidthMeasure = iota
+	// MeasureCells counts terminal cells, like DisplayWidth.
+	MeasureCells
+	// MeasureANSI counts terminal cells ignoring ANSI escape sequences, like VisibleWidth.
+	MeasureANSI
+)
+
+// width returns the width of s under m.
+func (m WidthMeasure) width(s string) int {
+	switch m {
+	case MeasureCells:
+		return DisplayWidth(s)
+	case MeasureANSI:
+		return VisibleWidth(s)
+	}
+	return LenRune(s)
+}
+
+// units splits s into the smallest pieces that may be separated when breaking a
+// word under m. Escape sequences stay attached to the character they precede.
+func (m WidthMeasure) units(s string) []string {
+	switch m {
+	case MeasureCells:
+		return splitGraphemes(s)
+	case MeasureANSI:
+		var units []string
+		pending := ""
+		eachANSIToken(s, func(token string, escape bool) {
+			if escape {
+				pending += token
+				return
+			}
+			units = append(units, pending+token)
+			pending = ""
+		})
+		if pending != "" {
+			if len(units) == 0 {
+				return []string{pending}
+			}
+			units[len(units)-1] += pending
+		}
+		return units
+	}
+	return splitRunes(s)
+}
+
+// WrapOptions configures WrapWithOptions and ReflowWithOptions.
+type WrapOptions struct {
+	// FirstLineIndent is prepended to the first line of each paragraph.
+	FirstLineIndent string
+	// Indent is prepended to every other line, giving a hanging indent.
+	Indent string
+	// BreakLongWords splits words that don't fit on a line by themselves.
+	// Otherwise such words overflow the width on a line of their own.
+	BreakLongWords bool
+	// Measure selects how widths are measured; the default counts runes.
+	Measure WidthMeasure
+}
+
+// Wrap wraps each line of s at spaces so that no line is longer than width runes.
+// Runs of spaces between words are collapsed to one, leading whitespace of each
+// line is kept, words longer than width overflow on a line of their own, and
+// the original line ending style of s is preserved.
+//
+// Example:
+//
+//	Wrap("the quick brown fox", 10) // Returns "the quick\nbrown fox"
+func Wrap(s string, width int) string {
+	return WrapWithOptions(s, width, WrapOptions{})
+}
+
+// WrapWithOptions is like Wrap but accepts options for indentation, breaking long
+// words and measuring width.
+func WrapWithOptions(s string, width int, opts WrapOptions) string {
+	// Detect original line ending style
+	lineEnding := GetLineEnding(s)
+
+	// Normalize all line endings to \n
+	lines := strings.Split(ToLinuxLineEnding(s), "\n")
+
+	var wrapped []string
+	for _, line := range lines {
+		wrapped = append(wrapped, wrapLine(line, width, opts)...)
+	}
+
+	// Join lines with original line ending
+	return strings.Join(wrapped, lineEnding)
+}
+
+// isWrapSpace reports whether r separates words when wrapping. Non-breaking
+// spaces are deliberately not included.
+func isWrapSpace(r rune) bool {
+	return r == ' ' || r == '\t'
+}
+
+// wrapLine wraps a single line without line breaks into one or more lines.
+func wrapLine(line string, width int, opts WrapOptions) []string {
+	words := strings.FieldsFunc(line, isWrapSpace)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	// Keep the original indentation of the line on its first wrapped line
+	leading := line[:strings.IndexFunc(line, func(r rune) bool { return !isWrapSpace(r) })]
+
+	var lines []string
+	var current strings.Builder
+	prefix := opts.FirstLineIndent + leading
+	current.WriteString(prefix)
+	available := max(width-opts.Measure.width(prefix), 1)
+	used := 0
+
+	newLine := func() {
+		lines = append(lines, current.String())
+		current.Reset()
+		current.WriteString(opts.Indent)
+		available = max(width-opts.Measure.width(opts.Indent), 1)
+		used = 0
+	}
+
+	for _, word := range words {
+		wordWidth := opts.Measure.width(word)
+
+		if used > 0 {
+			if used+1+wordWidth <= available {
+				current.WriteString(" ")
+				current.WriteString(word)
+				used += 1 + wordWidth
+				continue
+			}
+			newLine()
+		}
+
+		if wordWidth <= available || !opts.BreakLongWords {
+			current.WriteString(word)
+			used = wordWidth
+			continue
+		}
+
+		// Split the word into pieces that fill whole lines
+		for _, unit := range opts.Measure.units(word) {
+			unitWidth := opts.Measure.width(unit)
+			if used > 0 && used+unitWidth > available {
+				newLine()
+			}
+			current.WriteString(unit)
+			used += unitWidth
+		}
+	}
+
+	return append(lines, current.String())
+}
+
+// Reflow rejoins the hard-wrapped lines of each paragraph of s, where paragraphs
+// are separated by blank lines, and wraps the result with Wrap. Blank lines are
+// kept, as is the indentation of the first line of each paragraph.
+func Reflow(s string, width int) string {
+	return ReflowWithOptions(s, width, WrapOptions{})
+}
+
+// ReflowWithOptions is like Reflow but wraps with the given options.
+func ReflowWithOptions(s string, width int, opts WrapOptions) string {
+	// Detect original line ending style
+	lineEnding := GetLineEnding(s)
+
+	// Normalize all line endings to \n
+	lines := strings.Split(ToLinuxLineEnding(s), "\n")
+
+	var paragraphs []string
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			paragraphs = append(paragraphs, TrimRight(strings.Join(paragraph, " ")))
+			paragraph = nil
+		}
+	}
+
+	for _, line := range lines {
+		if Trim(line) == "" {
+			flush()
+			paragraphs = append(paragraphs, "")
+			continue
+		}
+		if len(paragraph) > 0 {
+			line = Trim(line)
+		}
+		paragraph = append(paragraph, line)
+	}
+	flush()
+
+	var wrapped []string
+	for _, p := range paragraphs {
+		wrapped = append(wrapped, wrapLine(p, width, opts)...)
+	}
+
+	return strings.Join(wrapped, lineEnding)
+}
@@ -0,0 +1,157 @@
+package s
+
+import "testing"
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		s        string
+		width    int
+		expected string
+	}{
+		{"", 10, ""},
+		{"hello", 10, "hello"},
+		{"the quick brown fox", 10, "the quick\nbrown fox"},
+		{"the quick brown fox", 9, "the quick\nbrown fox"},
+		{"the quick brown fox", 8, "the\nquick\nbrown\nfox"},
+		{"the  quick   brown fox", 10, "the quick\nbrown fox"},
+		{"  indented text here", 10, "  indented\ntext here"},
+		{"one\ntwo three four", 9, "one\ntwo three\nfour"},
+		{"one\r\ntwo three four", 9, "one\r\ntwo three\r\nfour"},
+		{"para one\n\npara two", 20, "para one\n\npara two"},
+		{"supercalifragilistic word", 10, "supercalifragilistic\nword"},
+		{"你好 世界 你好", 5, "你好 世界\n你好"},
+		{"non\u00a0breaking space", 10, "non\u00a0breaking\nspace"},
+	}
+
+	for _, test := range tests {
+		result := Wrap(test.s, test.width)
+		if result != test.expected {
+			t.Errorf("Wrap(%q, %d) = %q; want %q", test.s, test.width, result, test.expected)
+		}
+	}
+}
+
+func TestWrapWithOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		width    int
+		opts     WrapOptions
+		expected string
+	}{
+		{
+			name:     "hanging indent",
+			s:        "-v, --verbose  print every step of the build",
+			width:    20,
+			opts:     WrapOptions{Indent: "    "},
+			expected: "-v, --verbose print\n    every step of\n    the build",
+		},
+		{
+			name:     "first line indent",
+			s:        "the quick brown fox jumps",
+			width:    12,
+			opts:     WrapOptions{FirstLineIndent: "    "},
+			expected: "    the\nquick brown\nfox jumps",
+		},
+		{
+			name:     "both indents",
+			s:        "the quick brown fox jumps",
+			width:    12,
+			opts:     WrapOptions{FirstLineIndent: "* ", Indent: "  "},
+			expected: "* the quick\n  brown fox\n  jumps",
+		},
+		{
+			name:     "break long words",
+			s:        "see https://example.com/a/long/path now",
+			width:    10,
+			opts:     WrapOptions{BreakLongWords: true},
+			expected: "see\nhttps://ex\nample.com/\na/long/pat\nh now",
+		},
+		{
+			name:     "overflow long words",
+			s:        "see https://example.com/a/long/path now",
+			width:    10,
+			opts:     WrapOptions{},
+			expected: "see\nhttps://example.com/a/long/path\nnow",
+		},
+		{
+			name:     "measure runes",
+			s:        "你好 世界 你好",
+			width:    5,
+			opts:     WrapOptions{Measure: MeasureRunes},
+			expected: "你好 世界\n你好",
+		},
+		{
+			name:     "measure cells",
+			s:        "你好 世界 你好",
+			width:    5,
+			opts:     WrapOptions{Measure: MeasureCells},
+			expected: "你好\n世界\n你好",
+		},
+		{
+			name:     "break long words by cells",
+			s:        "你好世界",
+			width:    5,
+			opts:     WrapOptions{Measure: MeasureCells, BreakLongWords: true},
+			expected: "你好\n世界",
+		},
+		{
+			name:     "break long words keeps graphemes",
+			s:        "cafe\u0301cafe\u0301",
+			width:    5,
+			opts:     WrapOptions{Measure: MeasureCells, BreakLongWords: true},
+			expected: "cafe\u0301c\nafe\u0301",
+		},
+		{
+			name:     "break long words with ANSI",
+			s:        red + "abcdef" + reset,
+			width:    3,
+			opts:     WrapOptions{Measure: MeasureANSI, BreakLongWords: true},
+			expected: red + "abc\ndef" + reset,
+		},
+		{
+			name:     "windows line endings",
+			s:        "one two\r\nthree four",
+			width:    5,
+			opts:     WrapOptions{Indent: "> "},
+			expected: "one\r\n> two\r\nthree\r\n> four",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WrapWithOptions(tt.s, tt.width, tt.opts)
+			if got != tt.expected {
+				t.Errorf("WrapWithOptions(%q, %d, %+v) = %q; want %q", tt.s, tt.width, tt.opts, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReflow(t *testing.T) {
+	tests := []struct {
+		s        string
+		width    int
+		expected string
+	}{
+		{"", 10, ""},
+		{"the quick\nbrown fox\njumps", 20, "the quick brown fox\njumps"},
+		{"the quick\nbrown fox\n\njumps over\nthe dog", 40, "the quick brown fox\n\njumps over the dog"},
+		{"the quick\r\nbrown fox\r\n\r\njumps", 40, "the quick brown fox\r\n\r\njumps"},
+		{"  indented\n  paragraph text", 40, "  indented paragraph text"},
+		{"a\n\n\nb\n", 10, "a\n\n\nb\n"},
+		{"Fix the parser so that it\nhandles empty input.", 16, "Fix the parser\nso that it\nhandles empty\ninput."},
+	}
+
+	for _, test := range tests {
+		result := Reflow(test.s, test.width)
+		if result != test.expected {
+			t.Errorf("Reflow(%q, %d) = %q; want %q", test.s, test.width, result, test.expected)
+		}
+	}
+
+	got := ReflowWithOptions("one two\nthree four", 9, WrapOptions{Indent: "  "})
+	if want := "one two\n  three\n  four"; got != want {
+		t.Errorf("ReflowWithOptions with Indent = %q; want %q", got, want)
+	}
+}